@@ -0,0 +1,159 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// backupOffering is a backup offering as returned by listBackupOfferings,
+// which is not supported by the client
+type backupOffering struct {
+	Id                     string `json:"id"`
+	Name                   string `json:"name"`
+	Description            string `json:"description"`
+	Externalid             string `json:"externalid"`
+	Zoneid                 string `json:"zoneid"`
+	Zonename               string `json:"zonename"`
+	Allowuserdrivenbackups bool   `json:"allowuserdrivenbackups"`
+	Created                string `json:"created"`
+}
+
+func dataSourceCloudstackBackupOffering() *schema.Resource {
+	return &schema.Resource{
+		Read: datasourceCloudStackBackupOfferingRead,
+		Schema: map[string]*schema.Schema{
+			"filter": dataSourceFiltersSchema(),
+
+			"zone": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			//Computed values
+			"name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"external_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"zone_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"allow_user_driven_backups": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func datasourceCloudStackBackupOfferingRead(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	p := &cloudstack.CustomServiceParams{}
+	if zone, ok := d.GetOk("zone"); ok {
+		zoneid, e := retrieveID(cs, "zone", zone.(string))
+		if e != nil {
+			return e.Error()
+		}
+		p.SetParam("zoneid", zoneid)
+	}
+
+	var l struct {
+		Count           int               `json:"count"`
+		BackupOfferings []*backupOffering `json:"backupoffering"`
+	}
+	if err := customRequest(cs, "listBackupOfferings", p, &l); err != nil {
+		return fmt.Errorf("Failed to list backup offerings: %s", err)
+	}
+
+	filters := d.Get("filter")
+	var backupOfferings []*backupOffering
+
+	for _, o := range l.BackupOfferings {
+		match, err := applyBackupOfferingFilters(o, filters.(*schema.Set))
+		if err != nil {
+			return err
+		}
+		if match {
+			backupOfferings = append(backupOfferings, o)
+		}
+	}
+
+	if len(backupOfferings) == 0 {
+		return fmt.Errorf("No backup offering is matching with the specified regex")
+	}
+	if len(backupOfferings) > 1 {
+		return fmt.Errorf("More than one backup offering is matching with the specified regex")
+	}
+	backupOffering := backupOfferings[0]
+	log.Printf("[DEBUG] Selected backup offering: %s\n", backupOffering.Name)
+
+	return backupOfferingDescriptionAttributes(d, backupOffering)
+}
+
+func backupOfferingDescriptionAttributes(d *schema.ResourceData, backupOffering *backupOffering) error {
+	d.SetId(backupOffering.Id)
+	d.Set("name", backupOffering.Name)
+	d.Set("description", backupOffering.Description)
+	d.Set("external_id", backupOffering.Externalid)
+	d.Set("zone_id", backupOffering.Zoneid)
+	d.Set("allow_user_driven_backups", backupOffering.Allowuserdrivenbackups)
+
+	return nil
+}
+
+func applyBackupOfferingFilters(backupOffering *backupOffering, filters *schema.Set) (bool, error) {
+	var backupOfferingJSON map[string]interface{}
+	k, _ := json.Marshal(backupOffering)
+	err := json.Unmarshal(k, &backupOfferingJSON)
+	if err != nil {
+		return false, err
+	}
+
+	for _, f := range filters.List() {
+		m := f.(map[string]interface{})
+		r, err := regexp.Compile(m["value"].(string))
+		if err != nil {
+			return false, fmt.Errorf("Invalid regex: %s", err)
+		}
+		updatedName := strings.ReplaceAll(m["name"].(string), "_", "")
+		backupOfferingField := fmt.Sprint(backupOfferingJSON[updatedName])
+		if !r.MatchString(backupOfferingField) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
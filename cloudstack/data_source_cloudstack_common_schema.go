@@ -42,3 +42,11 @@ func dataSourceFiltersSchema() *schema.Schema {
 		},
 	}
 }
+
+func dataSourceOptionalFiltersSchema() *schema.Schema {
+	s := dataSourceFiltersSchema()
+	s.Required = false
+	s.Optional = true
+
+	return s
+}
@@ -0,0 +1,278 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"log"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// instancesPageSize is the number of instances to retrieve per request
+const instancesPageSize = 500
+
+func dataSourceCloudstackInstances() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceCloudstackInstancesRead,
+		Schema: map[string]*schema.Schema{
+			"filter": dataSourceOptionalFiltersSchema(),
+
+			"state": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			"zone": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			"network_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			"keyword": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			"project": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			"tags": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+
+			//Computed values
+			"instances": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"display_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"state": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"account": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"project": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"host_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"host_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"zone_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"zone_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"created": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"tags": {
+							Type:     schema.TypeMap,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"nic": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"id": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"network_id": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"network_name": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"is_default": {
+										Type:     schema.TypeBool,
+										Computed: true,
+									},
+									"mac_address": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"ip_address": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"ip6_address": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"ip6_cidr": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceCloudstackInstancesRead(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	p := cs.VirtualMachine.NewListVirtualMachinesParams()
+	p.SetListall(true)
+
+	// Use the filters supported by the API where possible
+	if state, ok := d.GetOk("state"); ok {
+		p.SetState(state.(string))
+	}
+
+	if zone, ok := d.GetOk("zone"); ok {
+		zoneid, e := retrieveID(cs, "zone", zone.(string))
+		if e != nil {
+			return e.Error()
+		}
+		p.SetZoneid(zoneid)
+	}
+
+	if networkid, ok := d.GetOk("network_id"); ok {
+		p.SetNetworkid(networkid.(string))
+	}
+
+	if keyword, ok := d.GetOk("keyword"); ok {
+		p.SetKeyword(keyword.(string))
+	}
+
+	if tags, ok := d.GetOk("tags"); ok {
+		p.SetTags(stringMapFromSchema(tags.(map[string]interface{})))
+	}
+
+	// If there is a project supplied, we retrieve and set the project id
+	if err := setProjectid(p, cs, d); err != nil {
+		return err
+	}
+
+	// Retrieve all pages, as CloudStack limits the number of results per call
+	var vms []*cloudstack.VirtualMachine
+	p.SetPagesize(instancesPageSize)
+	for page := 1; ; page++ {
+		p.SetPage(page)
+
+		l, err := cs.VirtualMachine.ListVirtualMachines(p)
+		if err != nil {
+			return fmt.Errorf("Failed to list instances: %s", err)
+		}
+
+		vms = append(vms, l.VirtualMachines...)
+		if len(l.VirtualMachines) == 0 || len(vms) >= l.Count {
+			break
+		}
+	}
+
+	filters := d.Get("filter").(*schema.Set)
+	h := sha1.New()
+
+	instances := make([]interface{}, 0, len(vms))
+	for _, i := range vms {
+		match, err := applyInstanceFilters(i, filters)
+		if err != nil {
+			return err
+		}
+		if !match {
+			continue
+		}
+
+		nics := make([]interface{}, 0, len(i.Nic))
+		for _, n := range i.Nic {
+			nics = append(nics, map[string]interface{}{
+				"id":           n.Id,
+				"network_id":   n.Networkid,
+				"network_name": n.Networkname,
+				"is_default":   n.Isdefault,
+				"mac_address":  n.Macaddress,
+				"ip_address":   n.Ipaddress,
+				"ip6_address":  n.Ip6address,
+				"ip6_cidr":     n.Ip6cidr,
+			})
+		}
+
+		instances = append(instances, map[string]interface{}{
+			"id":           i.Id,
+			"name":         i.Name,
+			"display_name": i.Displayname,
+			"state":        i.State,
+			"account":      i.Account,
+			"project":      i.Project,
+			"host_id":      i.Hostid,
+			"host_name":    i.Hostname,
+			"zone_id":      i.Zoneid,
+			"zone_name":    i.Zonename,
+			"created":      i.Created,
+			"tags":         tagsToMap(i.Tags),
+			"nic":          nics,
+		})
+
+		h.Write([]byte(i.Id))
+	}
+
+	log.Printf("[DEBUG] Found %d matching instances", len(instances))
+
+	d.SetId(hex.EncodeToString(h.Sum(nil)))
+
+	return d.Set("instances", instances)
+}
@@ -0,0 +1,92 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccInstancesDataSource_basic(t *testing.T) {
+	datasourceName := "data.cloudstack_instances.my_instances_test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccInstancesDataSourceConfig_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(datasourceName, "instances.#", "2"),
+					resource.TestCheckResourceAttr(datasourceName, "instances.0.state", "Running"),
+					resource.TestCheckResourceAttr(datasourceName, "instances.0.tags.role", "web"),
+					resource.TestCheckResourceAttrSet(datasourceName, "instances.0.nic.0.ip_address"),
+				),
+			},
+		},
+	})
+}
+
+const testAccInstancesDataSourceConfig_basic = `
+resource "cloudstack_instance" "web" {
+  count            = 2
+  name             = "web-${count.index}"
+  service_offering = "Small Instance"
+  template         = "CentOS 5.6 (64-bit) no GUI (Simulator)"
+  zone             = "Sandbox-simulator"
+  expunge          = true
+
+  tags = {
+    role = "web"
+  }
+}
+
+resource "cloudstack_instance" "db" {
+  name             = "db-0"
+  service_offering = "Small Instance"
+  template         = "CentOS 5.6 (64-bit) no GUI (Simulator)"
+  zone             = "Sandbox-simulator"
+  expunge          = true
+
+  tags = {
+    role = "db"
+  }
+}
+
+data "cloudstack_instances" "my_instances_test" {
+  zone  = "Sandbox-simulator"
+  state = "Running"
+
+  tags = {
+    role = "web"
+  }
+
+  filter {
+    name  = "name"
+    value = "^web-"
+  }
+
+  depends_on = [
+    cloudstack_instance.web,
+    cloudstack_instance.db,
+  ]
+}
+`
@@ -0,0 +1,213 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceCloudstackStoragePool() *schema.Resource {
+	return &schema.Resource{
+		Read: datasourceCloudStackStoragePoolRead,
+		Schema: map[string]*schema.Schema{
+			"filter": dataSourceFiltersSchema(),
+
+			"zone": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			//Computed values
+			"name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"zone_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"pod_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"cluster_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"scope": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"type": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"state": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"hypervisor": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"storage_provider": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"ip_address": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"path": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"tags": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"managed": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"disk_size_total": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"disk_size_allocated": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"disk_size_used": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"capacity_iops": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"allocated_iops": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"overprovision_factor": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func datasourceCloudStackStoragePoolRead(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+	p := cs.Pool.NewListStoragePoolsParams()
+
+	if zone, ok := d.GetOk("zone"); ok {
+		zoneid, e := retrieveID(cs, "zone", zone.(string))
+		if e != nil {
+			return e.Error()
+		}
+		p.SetZoneid(zoneid)
+	}
+
+	csStoragePools, err := cs.Pool.ListStoragePools(p)
+	if err != nil {
+		return fmt.Errorf("Failed to list storage pools: %s", err)
+	}
+
+	filters := d.Get("filter")
+	var storagePools []*cloudstack.StoragePool
+
+	for _, s := range csStoragePools.StoragePools {
+		match, err := applyStoragePoolFilters(s, filters.(*schema.Set))
+		if err != nil {
+			return err
+		}
+		if match {
+			storagePools = append(storagePools, s)
+		}
+	}
+
+	if len(storagePools) == 0 {
+		return fmt.Errorf("No storage pool is matching with the specified regex")
+	}
+	if len(storagePools) > 1 {
+		return fmt.Errorf("More than one storage pool is matching with the specified regex")
+	}
+	storagePool := storagePools[0]
+	log.Printf("[DEBUG] Selected storage pool: %s\n", storagePool.Name)
+
+	return storagePoolDescriptionAttributes(d, storagePool)
+}
+
+func storagePoolDescriptionAttributes(d *schema.ResourceData, storagePool *cloudstack.StoragePool) error {
+	d.SetId(storagePool.Id)
+	d.Set("name", storagePool.Name)
+	d.Set("zone_id", storagePool.Zoneid)
+	d.Set("pod_id", storagePool.Podid)
+	d.Set("cluster_id", storagePool.Clusterid)
+	d.Set("scope", storagePool.Scope)
+	d.Set("type", storagePool.Type)
+	d.Set("state", storagePool.State)
+	d.Set("hypervisor", storagePool.Hypervisor)
+	d.Set("storage_provider", storagePool.Provider)
+	d.Set("ip_address", storagePool.Ipaddress)
+	d.Set("path", storagePool.Path)
+	d.Set("tags", storagePool.Tags)
+	d.Set("managed", storagePool.Managed)
+	d.Set("disk_size_total", storagePool.Disksizetotal)
+	d.Set("disk_size_allocated", storagePool.Disksizeallocated)
+	d.Set("disk_size_used", storagePool.Disksizeused)
+	d.Set("capacity_iops", storagePool.Capacityiops)
+	d.Set("allocated_iops", storagePool.Allocatediops)
+	d.Set("overprovision_factor", storagePool.Overprovisionfactor)
+
+	return nil
+}
+
+func applyStoragePoolFilters(storagePool *cloudstack.StoragePool, filters *schema.Set) (bool, error) {
+	var storagePoolJSON map[string]interface{}
+	k, _ := json.Marshal(storagePool)
+	err := json.Unmarshal(k, &storagePoolJSON)
+	if err != nil {
+		return false, err
+	}
+
+	for _, f := range filters.List() {
+		m := f.(map[string]interface{})
+		r, err := regexp.Compile(m["value"].(string))
+		if err != nil {
+			return false, fmt.Errorf("Invalid regex: %s", err)
+		}
+		updatedName := strings.ReplaceAll(m["name"].(string), "_", "")
+		storagePoolField := fmt.Sprint(storagePoolJSON[updatedName])
+		if !r.MatchString(storagePoolField) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,68 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccStoragePoolDataSource_basic(t *testing.T) {
+	resourceName := "cloudstack_storage_pool.storage-pool-resource"
+	datasourceName := "data.cloudstack_storage_pool.storage-pool-data-source"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testStoragePoolDataSourceConfig_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(datasourceName, "name", resourceName, "name"),
+					resource.TestCheckResourceAttrPair(datasourceName, "scope", resourceName, "scope"),
+					resource.TestCheckResourceAttrSet(datasourceName, "disk_size_total"),
+					resource.TestCheckResourceAttrSet(datasourceName, "disk_size_used"),
+				),
+			},
+		},
+	})
+}
+
+const testStoragePoolDataSourceConfig_basic = `
+resource "cloudstack_storage_pool" "storage-pool-resource" {
+  name       = "terraform-pool-data-source"
+  url        = "nfs://10.147.28.6/export/home/sandbox/terraform-ds"
+  zone       = "Sandbox-simulator"
+  scope      = "zone"
+  hypervisor = "Simulator"
+  forced     = true
+}
+
+data "cloudstack_storage_pool" "storage-pool-data-source" {
+  filter {
+    name  = "name"
+    value = "terraform-pool-data-source"
+  }
+  depends_on = [
+    cloudstack_storage_pool.storage-pool-resource
+  ]
+}
+`
@@ -0,0 +1,224 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceCloudstackUnmanagedInstances() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceCloudstackUnmanagedInstancesRead,
+		Schema: map[string]*schema.Schema{
+			"cluster_id": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			"name": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			//Computed values
+			"instances": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"power_state": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"host_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"host_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"os_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"os_display_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"cpu_number": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"cpu_speed": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"memory": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"nic": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"id": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"mac_address": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"network_name": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"vlan_id": {
+										Type:     schema.TypeInt,
+										Computed: true,
+									},
+									"adapter_type": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"ip_addresses": {
+										Type:     schema.TypeList,
+										Computed: true,
+										Elem:     &schema.Schema{Type: schema.TypeString},
+									},
+								},
+							},
+						},
+						"disk": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"id": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"label": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"capacity": {
+										Type:     schema.TypeInt,
+										Computed: true,
+									},
+									"position": {
+										Type:     schema.TypeInt,
+										Computed: true,
+									},
+									"controller": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"image_path": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"datastore_name": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceCloudstackUnmanagedInstancesRead(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	clusterid := d.Get("cluster_id").(string)
+
+	p := cs.VirtualMachine.NewListUnmanagedInstancesParams(clusterid)
+	if name, ok := d.GetOk("name"); ok {
+		p.SetName(name.(string))
+	}
+
+	l, err := cs.VirtualMachine.ListUnmanagedInstances(p)
+	if err != nil {
+		return fmt.Errorf("Failed to list unmanaged instances of cluster %s: %s", clusterid, err)
+	}
+
+	log.Printf("[DEBUG] Found %d unmanaged instances in cluster %s", l.Count, clusterid)
+
+	instances := make([]interface{}, 0, len(l.UnmanagedInstances))
+	for _, i := range l.UnmanagedInstances {
+		nics := make([]interface{}, 0, len(i.Nic))
+		for _, n := range i.Nic {
+			nics = append(nics, map[string]interface{}{
+				"id":           n.Id,
+				"mac_address":  n.Macaddress,
+				"network_name": n.Networkname,
+				"vlan_id":      n.Vlanid,
+				"adapter_type": n.Adaptertype,
+				"ip_addresses": n.Ipaddresses,
+			})
+		}
+
+		disks := make([]interface{}, 0, len(i.Disk))
+		for _, disk := range i.Disk {
+			disks = append(disks, map[string]interface{}{
+				"id":             disk.Id,
+				"label":          disk.Label,
+				"capacity":       disk.Capacity,
+				"position":       disk.Position,
+				"controller":     disk.Controller,
+				"image_path":     disk.Imagepath,
+				"datastore_name": disk.Datastorename,
+			})
+		}
+
+		instances = append(instances, map[string]interface{}{
+			"name":            i.Name,
+			"power_state":     i.Powerstate,
+			"host_id":         i.Hostid,
+			"host_name":       i.Hostname,
+			"os_id":           i.Osid,
+			"os_display_name": i.Osdisplayname,
+			"cpu_number":      i.Cpunumber,
+			"cpu_speed":       i.Cpuspeed,
+			"memory":          i.Memory,
+			"nic":             nics,
+			"disk":            disks,
+		})
+	}
+
+	d.SetId(clusterid)
+
+	return d.Set("instances", instances)
+}
@@ -30,8 +30,8 @@ func TestAccVolumeDataSource_basic(t *testing.T) {
 	datasourceName := "data.cloudstack_volume.volume-data-source"
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccMuxProvider,
 		Steps: []resource.TestStep{
 			{
 				Config: testVolumeDataSourceConfig_basic,
@@ -0,0 +1,85 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+)
+
+// encryptWithPGPKey encrypts the value with the given PGP public key, which
+// is either base64 encoded or ASCII armored. It returns the fingerprint of
+// the key and the base64 encoded encrypted value.
+func encryptWithPGPKey(key, value string) (string, string, error) {
+	entity, err := readPGPKey(key)
+	if err != nil {
+		return "", "", fmt.Errorf("Error reading PGP key: %s", err)
+	}
+
+	buf := new(bytes.Buffer)
+	w, err := openpgp.Encrypt(buf, []*openpgp.Entity{entity}, nil, nil, nil)
+	if err != nil {
+		return "", "", fmt.Errorf("Error encrypting with PGP key: %s", err)
+	}
+
+	if _, err := w.Write([]byte(value)); err != nil {
+		return "", "", fmt.Errorf("Error encrypting with PGP key: %s", err)
+	}
+
+	if err := w.Close(); err != nil {
+		return "", "", fmt.Errorf("Error encrypting with PGP key: %s", err)
+	}
+
+	fingerprint := hex.EncodeToString(entity.PrimaryKey.Fingerprint)
+
+	return fingerprint, base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// readPGPKey returns the first entity of a base64 encoded or ASCII armored
+// PGP public key
+func readPGPKey(key string) (*openpgp.Entity, error) {
+	var entities openpgp.EntityList
+	var err error
+
+	if strings.Contains(key, "-----BEGIN PGP PUBLIC KEY BLOCK-----") {
+		entities, err = openpgp.ReadArmoredKeyRing(strings.NewReader(key))
+	} else {
+		var b []byte
+		b, err = base64.StdEncoding.DecodeString(strings.TrimSpace(key))
+		if err != nil {
+			return nil, fmt.Errorf("key is neither base64 encoded nor ASCII armored: %s", err)
+		}
+		entities, err = openpgp.ReadKeyRing(bytes.NewReader(b))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(entities) == 0 {
+		return nil, fmt.Errorf("key does not contain any entities")
+	}
+
+	return entities[0], nil
+}
@@ -0,0 +1,105 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+)
+
+func TestEncryptWithPGPKey(t *testing.T) {
+	entity, key := testPGPKey(t)
+
+	var armored bytes.Buffer
+	w, err := armor.Encode(&armored, openpgp.PublicKeyType, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := entity.Serialize(w); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+
+	for name, k := range map[string]string{
+		"base64":  key,
+		"armored": armored.String(),
+	} {
+		fingerprint, encrypted, err := encryptWithPGPKey(k, "s3cr3t")
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", name, err)
+		}
+
+		if fingerprint != hex.EncodeToString(entity.PrimaryKey.Fingerprint) {
+			t.Errorf("%s: bad fingerprint: %s", name, fingerprint)
+		}
+
+		if decrypted := testPGPDecrypt(t, entity, encrypted); decrypted != "s3cr3t" {
+			t.Errorf("%s: bad decrypted value: %s", name, decrypted)
+		}
+	}
+}
+
+func TestEncryptWithPGPKey_invalid(t *testing.T) {
+	if _, _, err := encryptWithPGPKey("not a key", "s3cr3t"); err == nil {
+		t.Fatal("expected an error for an invalid key")
+	}
+}
+
+// testPGPKey generates a new PGP entity and returns it together with its
+// base64 encoded public key
+func testPGPKey(t *testing.T) (*openpgp.Entity, string) {
+	entity, err := openpgp.NewEntity("terraform", "test", "terraform@example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := entity.Serialize(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	return entity, base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+// testPGPDecrypt decrypts a base64 encoded value encrypted for the entity
+func testPGPDecrypt(t *testing.T, entity *openpgp.Entity, encrypted string) string {
+	b, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	md, err := openpgp.ReadMessage(bytes.NewReader(b), openpgp.EntityList{entity}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decrypted, err := io.ReadAll(md.UnverifiedBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(decrypted)
+}
@@ -78,59 +78,73 @@ func Provider() *schema.Provider {
 		},
 
 		DataSourcesMap: map[string]*schema.Resource{
-			"cloudstack_template":         dataSourceCloudstackTemplate(),
-			"cloudstack_ssh_keypair":      dataSourceCloudstackSSHKeyPair(),
-			"cloudstack_instance":         dataSourceCloudstackInstance(),
-			"cloudstack_network_offering": dataSourceCloudstackNetworkOffering(),
-			"cloudstack_zone":             dataSourceCloudStackZone(),
-			"cloudstack_service_offering": dataSourceCloudstackServiceOffering(),
-			"cloudstack_volume":           dataSourceCloudstackVolume(),
-			"cloudstack_vpc":              dataSourceCloudstackVPC(),
-			"cloudstack_ipaddress":        dataSourceCloudstackIPAddress(),
-			"cloudstack_user":             dataSourceCloudstackUser(),
-			"cloudstack_vpn_connection":   dataSourceCloudstackVPNConnection(),
-			"cloudstack_pod":              dataSourceCloudstackPod(),
+			"cloudstack_template":            dataSourceCloudstackTemplate(),
+			"cloudstack_ssh_keypair":         dataSourceCloudstackSSHKeyPair(),
+			"cloudstack_instance":            dataSourceCloudstackInstance(),
+			"cloudstack_instances":           dataSourceCloudstackInstances(),
+			"cloudstack_network_offering":    dataSourceCloudstackNetworkOffering(),
+			"cloudstack_zone":                dataSourceCloudStackZone(),
+			"cloudstack_service_offering":    dataSourceCloudstackServiceOffering(),
+			"cloudstack_volume":              dataSourceCloudstackVolume(),
+			"cloudstack_vpc":                 dataSourceCloudstackVPC(),
+			"cloudstack_ipaddress":           dataSourceCloudstackIPAddress(),
+			"cloudstack_user":                dataSourceCloudstackUser(),
+			"cloudstack_vpn_connection":      dataSourceCloudstackVPNConnection(),
+			"cloudstack_pod":                 dataSourceCloudstackPod(),
+			"cloudstack_backup_offering":     dataSourceCloudstackBackupOffering(),
+			"cloudstack_unmanaged_instances": dataSourceCloudstackUnmanagedInstances(),
+			"cloudstack_storage_pool":        dataSourceCloudstackStoragePool(),
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
-			"cloudstack_affinity_group":       resourceCloudStackAffinityGroup(),
-			"cloudstack_attach_volume":        resourceCloudStackAttachVolume(),
-			"cloudstack_autoscale_vm_profile": resourceCloudStackAutoScaleVMProfile(),
-			"cloudstack_configuration":        resourceCloudStackConfiguration(),
-			"cloudstack_disk":                 resourceCloudStackDisk(),
-			"cloudstack_egress_firewall":      resourceCloudStackEgressFirewall(),
-			"cloudstack_firewall":             resourceCloudStackFirewall(),
-			"cloudstack_host":                 resourceCloudStackHost(),
-			"cloudstack_instance":             resourceCloudStackInstance(),
-			"cloudstack_ipaddress":            resourceCloudStackIPAddress(),
-			"cloudstack_kubernetes_cluster":   resourceCloudStackKubernetesCluster(),
-			"cloudstack_kubernetes_version":   resourceCloudStackKubernetesVersion(),
-			"cloudstack_loadbalancer_rule":    resourceCloudStackLoadBalancerRule(),
-			"cloudstack_network":              resourceCloudStackNetwork(),
-			"cloudstack_network_acl":          resourceCloudStackNetworkACL(),
-			"cloudstack_network_acl_rule":     resourceCloudStackNetworkACLRule(),
-			"cloudstack_nic":                  resourceCloudStackNIC(),
-			"cloudstack_port_forward":         resourceCloudStackPortForward(),
-			"cloudstack_private_gateway":      resourceCloudStackPrivateGateway(),
-			"cloudstack_secondary_ipaddress":  resourceCloudStackSecondaryIPAddress(),
-			"cloudstack_security_group":       resourceCloudStackSecurityGroup(),
-			"cloudstack_security_group_rule":  resourceCloudStackSecurityGroupRule(),
-			"cloudstack_ssh_keypair":          resourceCloudStackSSHKeyPair(),
-			"cloudstack_static_nat":           resourceCloudStackStaticNAT(),
-			"cloudstack_static_route":         resourceCloudStackStaticRoute(),
-			"cloudstack_template":             resourceCloudStackTemplate(),
-			"cloudstack_vpc":                  resourceCloudStackVPC(),
-			"cloudstack_vpn_connection":       resourceCloudStackVPNConnection(),
-			"cloudstack_vpn_customer_gateway": resourceCloudStackVPNCustomerGateway(),
-			"cloudstack_vpn_gateway":          resourceCloudStackVPNGateway(),
-			"cloudstack_network_offering":     resourceCloudStackNetworkOffering(),
-			"cloudstack_disk_offering":        resourceCloudStackDiskOffering(),
-			"cloudstack_volume":               resourceCloudStackVolume(),
-			"cloudstack_zone":                 resourceCloudStackZone(),
-			"cloudstack_service_offering":     resourceCloudStackServiceOffering(),
-			"cloudstack_account":              resourceCloudStackAccount(),
-			"cloudstack_user":                 resourceCloudStackUser(),
-			"cloudstack_domain":               resourceCloudStackDomain(),
+			"cloudstack_affinity_group":            resourceCloudStackAffinityGroup(),
+			"cloudstack_attach_volume":             resourceCloudStackAttachVolume(),
+			"cloudstack_autoscale_vm_profile":      resourceCloudStackAutoScaleVMProfile(),
+			"cloudstack_backup":                    resourceCloudStackBackup(),
+			"cloudstack_configuration":             resourceCloudStackConfiguration(),
+			"cloudstack_disk":                      resourceCloudStackDisk(),
+			"cloudstack_egress_firewall":           resourceCloudStackEgressFirewall(),
+			"cloudstack_firewall":                  resourceCloudStackFirewall(),
+			"cloudstack_image_store":               resourceCloudStackImageStore(),
+			"cloudstack_host":                      resourceCloudStackHost(),
+			"cloudstack_instance":                  resourceCloudStackInstance(),
+			"cloudstack_instance_backup_policy":    resourceCloudStackInstanceBackupPolicy(),
+			"cloudstack_instance_schedule":         resourceCloudStackInstanceSchedule(),
+			"cloudstack_ipaddress":                 resourceCloudStackIPAddress(),
+			"cloudstack_kubernetes_cluster":        resourceCloudStackKubernetesCluster(),
+			"cloudstack_kubernetes_version":        resourceCloudStackKubernetesVersion(),
+			"cloudstack_loadbalancer_rule":         resourceCloudStackLoadBalancerRule(),
+			"cloudstack_network":                   resourceCloudStackNetwork(),
+			"cloudstack_network_acl":               resourceCloudStackNetworkACL(),
+			"cloudstack_network_acl_rule":          resourceCloudStackNetworkACLRule(),
+			"cloudstack_nic":                       resourceCloudStackNIC(),
+			"cloudstack_port_forward":              resourceCloudStackPortForward(),
+			"cloudstack_private_gateway":           resourceCloudStackPrivateGateway(),
+			"cloudstack_secondary_ipaddress":       resourceCloudStackSecondaryIPAddress(),
+			"cloudstack_security_group":            resourceCloudStackSecurityGroup(),
+			"cloudstack_security_group_rule":       resourceCloudStackSecurityGroupRule(),
+			"cloudstack_secondary_staging_store":   resourceCloudStackSecondaryStagingStore(),
+			"cloudstack_snapshot":                  resourceCloudStackSnapshot(),
+			"cloudstack_snapshot_policy":           resourceCloudStackSnapshotPolicy(),
+			"cloudstack_ssh_keypair":               resourceCloudStackSSHKeyPair(),
+			"cloudstack_static_nat":                resourceCloudStackStaticNAT(),
+			"cloudstack_static_route":              resourceCloudStackStaticRoute(),
+			"cloudstack_storage_pool":              resourceCloudStackStoragePool(),
+			"cloudstack_template":                  resourceCloudStackTemplate(),
+			"cloudstack_unmanaged_instance_import": resourceCloudStackUnmanagedInstanceImport(),
+			"cloudstack_user_data":                 resourceCloudStackUserData(),
+			"cloudstack_vm_snapshot":               resourceCloudStackVMSnapshot(),
+			"cloudstack_vpc":                       resourceCloudStackVPC(),
+			"cloudstack_vpn_connection":            resourceCloudStackVPNConnection(),
+			"cloudstack_vpn_customer_gateway":      resourceCloudStackVPNCustomerGateway(),
+			"cloudstack_vpn_gateway":               resourceCloudStackVPNGateway(),
+			"cloudstack_network_offering":          resourceCloudStackNetworkOffering(),
+			"cloudstack_disk_offering":             resourceCloudStackDiskOffering(),
+			"cloudstack_zone":                      resourceCloudStackZone(),
+			"cloudstack_service_offering":          resourceCloudStackServiceOffering(),
+			"cloudstack_account":                   resourceCloudStackAccount(),
+			"cloudstack_user":                      resourceCloudStackUser(),
+			"cloudstack_domain":                    resourceCloudStackDomain(),
 		},
 
 		ConfigureFunc: providerConfigure,
@@ -40,6 +40,10 @@ var testAccMuxProvider map[string]func() (tfprotov6.ProviderServer, error)
 
 var cloudStackTemplateURL = os.Getenv("CLOUDSTACK_TEMPLATE_URL")
 
+var cloudStackVolumeURL = os.Getenv("CLOUDSTACK_VOLUME_URL")
+
+var cloudStackSecondZone = os.Getenv("CLOUDSTACK_SECOND_ZONE")
+
 func init() {
 	testAccProvider = Provider()
 	testAccProviders = map[string]*schema.Provider{
@@ -50,9 +54,10 @@ func init() {
 		"cloudstack": func() (tfprotov6.ProviderServer, error) {
 			ctx := context.Background()
 
+			// Use the shared provider, so its client can be used by the checks
 			upgradedSdkServer, err := tf5to6server.UpgradeServer(
 				ctx,
-				Provider().GRPCProvider,
+				testAccProvider.GRPCProvider,
 			)
 
 			if err != nil {
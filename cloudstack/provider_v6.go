@@ -6,6 +6,8 @@ import (
 	"os"
 	"strconv"
 
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/go-ini/ini"
 	"github.com/hashicorp/terraform-plugin-framework-validators/providervalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -15,7 +17,11 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
-type CloudstackProvider struct{}
+type CloudstackProvider struct {
+	// client is the configured client, used where the framework does not
+	// configure resources (like when moving resource state)
+	client *cloudstack.CloudStackClient
+}
 
 type CloudstackProviderModel struct {
 	ApiUrl      types.String `tfsdk:"api_url"`
@@ -90,6 +96,24 @@ func (p *CloudstackProvider) Configure(ctx context.Context, req provider.Configu
 		secretKey = data.SecretKey.ValueString()
 	}
 
+	if data.Config.ValueString() != "" && data.Profile.ValueString() != "" {
+		cfg, err := ini.Load(data.Config.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("cloudstack", fmt.Sprintf("failed to load config: %s", err))
+			return
+		}
+
+		section, err := cfg.GetSection(data.Profile.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("cloudstack", fmt.Sprintf("failed to load profile: %s", err))
+			return
+		}
+
+		apiUrl = section.Key("url").String()
+		apiKey = section.Key("apikey").String()
+		secretKey = section.Key("secretkey").String()
+	}
+
 	if data.HttpGetOnly.ValueBool() {
 		httpGetOnly = true
 	}
@@ -113,6 +137,8 @@ func (p *CloudstackProvider) Configure(ctx context.Context, req provider.Configu
 		return
 	}
 
+	p.client = client
+
 	resp.ResourceData = client
 	resp.DataSourceData = client
 }
@@ -147,7 +173,9 @@ func (p *CloudstackProvider) ConfigValidators(ctx context.Context) []provider.Co
 }
 
 func (p *CloudstackProvider) Resources(ctx context.Context) []func() resource.Resource {
-	return []func() resource.Resource{}
+	return []func() resource.Resource{
+		newVolumeResource(p),
+	}
 }
 
 func (p *CloudstackProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
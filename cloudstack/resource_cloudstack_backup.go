@@ -0,0 +1,344 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// backup is a backup as returned by listBackups, which is not supported by
+// the client
+type backup struct {
+	Id               string `json:"id"`
+	Virtualmachineid string `json:"virtualmachineid"`
+	Externalid       string `json:"externalid"`
+	Type             string `json:"type"`
+	Date             string `json:"date"`
+	Size             int64  `json:"size"`
+	Virtualsize      int64  `json:"virtualsize"`
+	Status           string `json:"status"`
+	Backupofferingid string `json:"backupofferingid"`
+	Zoneid           string `json:"zoneid"`
+}
+
+func resourceCloudStackBackup() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceCloudStackBackupCreate,
+		Read:   resourceCloudStackBackupRead,
+		Update: resourceCloudStackBackupUpdate,
+		Delete: resourceCloudStackBackupDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: importStatePassthroughContext,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"virtual_machine_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"restore_trigger": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			"project": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"forced_deletion": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+
+			"backup_offering_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"external_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"type": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"status": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"date": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"size": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+
+			"virtual_size": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+
+			"zone_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceCloudStackBackupCreate(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	virtualmachineid := d.Get("virtual_machine_id").(string)
+
+	// Create a new parameter struct
+	p := &cloudstack.CustomServiceParams{}
+	p.SetParam("virtualmachineid", virtualmachineid)
+
+	// The backup is created asynchronously, so keep the ID of the new backup
+	// when it is returned together with the job ID
+	var job struct {
+		ID    string `json:"id"`
+		JobID string `json:"jobid"`
+	}
+
+	log.Printf("[DEBUG] Creating backup of virtual machine %s", virtualmachineid)
+	if err := customRequest(cs, "createBackup", p, &job); err != nil {
+		return fmt.Errorf("Error creating backup of virtual machine %s: %s", virtualmachineid, err)
+	}
+
+	timeout := int64(d.Timeout(schema.TimeoutCreate).Seconds())
+	if _, err := cs.GetAsyncJobResult(job.JobID, timeout); err != nil {
+		return fmt.Errorf("Error creating backup of virtual machine %s: %s", virtualmachineid, err)
+	}
+
+	// Otherwise the new backup is the instance the job was created for
+	if job.ID == "" {
+		r, err := cs.Asyncjob.QueryAsyncJobResult(cs.Asyncjob.NewQueryAsyncJobResultParams(job.JobID))
+		if err != nil {
+			return err
+		}
+
+		if r.Jobinstancetype == "Backup" {
+			job.ID = r.Jobinstanceid
+		}
+	}
+
+	if job.ID == "" {
+		return fmt.Errorf(
+			"Error creating backup of virtual machine %s: the ID of the new backup is not returned", virtualmachineid)
+	}
+
+	d.SetId(job.ID)
+
+	return resourceCloudStackBackupRead(d, meta)
+}
+
+func resourceCloudStackBackupRead(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	projectid, err := backupProjectID(cs, d)
+	if err != nil {
+		return err
+	}
+
+	backups, err := listBackups(cs, d.Id(), "", projectid)
+	if err != nil {
+		return err
+	}
+
+	if len(backups) == 0 {
+		log.Printf("[DEBUG] Backup %s does no longer exist", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	b := backups[0]
+
+	d.Set("virtual_machine_id", b.Virtualmachineid)
+	d.Set("backup_offering_id", b.Backupofferingid)
+	d.Set("external_id", b.Externalid)
+	d.Set("type", b.Type)
+	d.Set("status", b.Status)
+	d.Set("date", b.Date)
+	d.Set("size", b.Size)
+	d.Set("virtual_size", b.Virtualsize)
+	d.Set("zone_id", b.Zoneid)
+
+	// Backups do not return their project, so use the project of the virtual
+	// machine if it still exists
+	vm, count, err := cs.VirtualMachine.GetVirtualMachineByID(
+		b.Virtualmachineid,
+		cloudstack.WithProject(d.Get("project").(string)),
+	)
+	if err != nil && count != 0 {
+		return err
+	}
+	if err == nil {
+		setValueOrID(d, "project", vm.Project, vm.Projectid)
+	}
+
+	return nil
+}
+
+func resourceCloudStackBackupUpdate(d *schema.ResourceData, meta interface{}) error {
+	// Check if the restore trigger has changed and if so, restore the
+	// virtual machine from this backup
+	if d.HasChange("restore_trigger") {
+		if err := resourceCloudStackBackupRestore(d, meta); err != nil {
+			return fmt.Errorf("Error restoring backup %s: %s", d.Id(), err)
+		}
+	}
+
+	return resourceCloudStackBackupRead(d, meta)
+}
+
+// resourceCloudStackBackupRestore restores the virtual machine from the
+// backup. A virtual machine can only be restored while it is stopped, so a
+// running virtual machine is stopped first and started again afterwards.
+func resourceCloudStackBackupRestore(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	virtualmachineid := d.Get("virtual_machine_id").(string)
+
+	vm, _, err := cs.VirtualMachine.GetVirtualMachineByID(
+		virtualmachineid,
+		cloudstack.WithProject(d.Get("project").(string)),
+	)
+	if err != nil {
+		return err
+	}
+
+	restart := vm.State == "Running"
+	if restart {
+		_, err := cs.VirtualMachine.StopVirtualMachine(
+			cs.VirtualMachine.NewStopVirtualMachineParams(virtualmachineid))
+		if err != nil {
+			return fmt.Errorf("Error stopping virtual machine %s: %s", virtualmachineid, err)
+		}
+	}
+
+	// Create a new parameter struct
+	p := &cloudstack.CustomServiceParams{}
+	p.SetParam("id", d.Id())
+
+	log.Printf("[DEBUG] Restoring virtual machine %s from backup %s", virtualmachineid, d.Id())
+	if err := customAsyncRequest(cs, "restoreBackup", p, nil, d.Timeout(schema.TimeoutUpdate)); err != nil {
+		return err
+	}
+
+	if restart {
+		_, err := cs.VirtualMachine.StartVirtualMachine(
+			cs.VirtualMachine.NewStartVirtualMachineParams(virtualmachineid))
+		if err != nil {
+			return fmt.Errorf("Error starting virtual machine %s: %s", virtualmachineid, err)
+		}
+	}
+
+	return nil
+}
+
+func resourceCloudStackBackupDelete(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Create a new parameter struct
+	p := &cloudstack.CustomServiceParams{}
+	p.SetParam("id", d.Id())
+	p.SetParam("forced", d.Get("forced_deletion").(bool))
+
+	log.Printf("[INFO] Deleting backup: %s", d.Id())
+	if err := customAsyncRequest(cs, "deleteBackup", p, nil, d.Timeout(schema.TimeoutDelete)); err != nil {
+		// This is a very poor way to be told the ID does no longer exist :(
+		if strings.Contains(err.Error(), fmt.Sprintf(
+			"Invalid parameter id value=%s due to incorrect long value format, "+
+				"or entity does not exist", d.Id())) {
+			return nil
+		}
+
+		return fmt.Errorf("Error deleting backup %s: %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+// backupProjectID returns the ID of the configured project, if any
+func backupProjectID(cs *cloudstack.CloudStackClient, d *schema.ResourceData) (string, error) {
+	project, ok := d.GetOk("project")
+	if !ok {
+		return "", nil
+	}
+
+	projectid, e := retrieveID(cs, "project", project.(string))
+	if e != nil {
+		return "", e.Error()
+	}
+
+	return projectid, nil
+}
+
+// listBackups returns the backup with the given ID, or the backups of the
+// given virtual machine
+func listBackups(cs *cloudstack.CloudStackClient, id, virtualmachineid, projectid string) ([]*backup, error) {
+	p := &cloudstack.CustomServiceParams{}
+	if id != "" {
+		p.SetParam("id", id)
+	}
+	if virtualmachineid != "" {
+		p.SetParam("virtualmachineid", virtualmachineid)
+	}
+	if projectid != "" {
+		p.SetParam("projectid", projectid)
+	}
+
+	var l struct {
+		Count   int       `json:"count"`
+		Backups []*backup `json:"backup"`
+	}
+	if err := customRequest(cs, "listBackups", p, &l); err != nil {
+		return nil, err
+	}
+
+	return l.Backups, nil
+}
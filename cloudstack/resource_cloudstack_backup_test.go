@@ -0,0 +1,144 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccCloudStackBackup_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackBackupDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackBackup_basic(""),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackBackupExists("cloudstack_backup.foo"),
+					resource.TestCheckResourceAttrPair(
+						"cloudstack_backup.foo", "virtual_machine_id",
+						"cloudstack_instance.foobar", "id"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_backup.foo", "status", "BackedUp"),
+				),
+			},
+
+			{
+				Config: testAccCloudStackBackup_basic("restore-1"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackBackupExists("cloudstack_backup.foo"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_backup.foo", "restore_trigger", "restore-1"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckCloudStackBackupExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No backup ID is set")
+		}
+
+		cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+		backups, err := listBackups(cs, rs.Primary.ID, "", "")
+		if err != nil {
+			return err
+		}
+
+		if len(backups) != 1 || backups[0].Id != rs.Primary.ID {
+			return fmt.Errorf("Backup not found")
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckCloudStackBackupDestroy(s *terraform.State) error {
+	cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "cloudstack_backup" {
+			continue
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No backup ID is set")
+		}
+
+		backups, err := listBackups(cs, rs.Primary.ID, "", "")
+		if err == nil && len(backups) > 0 {
+			return fmt.Errorf("Backup %s still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+func testAccCloudStackBackup_basic(trigger string) string {
+	return fmt.Sprintf(`
+resource "cloudstack_network" "foo" {
+  name = "terraform-network"
+  display_text = "terraform-network"
+  cidr = "10.1.1.0/24"
+  network_offering = "DefaultIsolatedNetworkOfferingWithSourceNatService"
+  zone = "Sandbox-simulator"
+}
+
+resource "cloudstack_instance" "foobar" {
+  name = "terraform-test"
+  display_name = "terraform-test"
+  service_offering= "Small Instance"
+  network_id = cloudstack_network.foo.id
+  template = "CentOS 5.6 (64-bit) no GUI (Simulator)"
+  zone = "Sandbox-simulator"
+  expunge = true
+}
+
+data "cloudstack_backup_offering" "foo" {
+  filter {
+    name = "name"
+    value = "Dummy Offering"
+  }
+}
+
+resource "cloudstack_instance_backup_policy" "foo" {
+  virtual_machine_id = cloudstack_instance.foobar.id
+  backup_offering_id = data.cloudstack_backup_offering.foo.id
+  forced_removal = true
+}
+
+resource "cloudstack_backup" "foo" {
+  virtual_machine_id = cloudstack_instance_backup_policy.foo.virtual_machine_id
+  restore_trigger = "%s"
+}`, trigger)
+}
@@ -21,6 +21,7 @@ package cloudstack
 
 import (
 	"fmt"
+	"log"
 	"strings"
 
 	"github.com/apache/cloudstack-go/v2/cloudstack"
@@ -59,6 +60,47 @@ func resourceCloudStackDisk() *schema.Resource {
 			"disk_offering": {
 				Type:     schema.TypeString,
 				Optional: true,
+				Computed: true,
+			},
+
+			"snapshot_id": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"url"},
+			},
+
+			"url": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				RequiredWith:  []string{"format"},
+				ConflictsWith: []string{"size"},
+			},
+
+			"format": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				RequiredWith: []string{"url"},
+			},
+
+			"checksum": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				RequiredWith: []string{"url"},
+			},
+
+			"upload_timeout": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  300,
+			},
+
+			"revert_to_snapshot": {
+				Type:     schema.TypeString,
+				Optional: true,
 			},
 
 			"size": {
@@ -79,6 +121,12 @@ func resourceCloudStackDisk() *schema.Resource {
 				Computed: true,
 			},
 
+			"storage_pool_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+
 			"project": {
 				Type:     schema.TypeString,
 				Optional: true,
@@ -108,17 +156,30 @@ func resourceCloudStackDiskCreate(d *schema.ResourceData, meta interface{}) erro
 
 	name := d.Get("name").(string)
 
+	// A volume with a URL is uploaded instead of created
+	if _, ok := d.GetOk("url"); ok {
+		return resourceCloudStackDiskUpload(d, meta)
+	}
+
 	// Create a new parameter struct
 	p := cs.Volume.NewCreateVolumeParams()
 	p.SetName(name)
 
-	// Retrieve the disk_offering ID
-	diskofferingid, e := retrieveID(cs, "disk_offering", d.Get("disk_offering").(string))
-	if e != nil {
-		return e.Error()
+	// A volume created from a snapshot can use the disk offering of the snapshot
+	if diskoffering, ok := d.GetOk("disk_offering"); ok {
+		// Retrieve the disk_offering ID
+		diskofferingid, e := retrieveID(cs, "disk_offering", diskoffering.(string))
+		if e != nil {
+			return e.Error()
+		}
+		// Set the disk_offering ID
+		p.SetDiskofferingid(diskofferingid)
+	}
+
+	if snapshotid, ok := d.GetOk("snapshot_id"); ok {
+		// Set the snapshot ID to create the volume from
+		p.SetSnapshotid(snapshotid.(string))
 	}
-	// Set the disk_offering ID
-	p.SetDiskofferingid(diskofferingid)
 
 	if d.Get("size").(int) != 0 {
 		// Set the volume size
@@ -147,8 +208,93 @@ func resourceCloudStackDiskCreate(d *schema.ResourceData, meta interface{}) erro
 	// Set the volume ID and partials
 	d.SetId(r.Id)
 
+	return resourceCloudStackDiskCreated(d, meta)
+}
+
+// resourceCloudStackDiskUpload uploads a new volume from a URL and waits until
+// the upload is finished
+func resourceCloudStackDiskUpload(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	name := d.Get("name").(string)
+
+	// Retrieve the zone ID
+	zoneid, e := retrieveID(cs, "zone", d.Get("zone").(string))
+	if e != nil {
+		return e.Error()
+	}
+
+	// Create a new parameter struct
+	p := cs.Volume.NewUploadVolumeParams(
+		d.Get("format").(string),
+		name,
+		d.Get("url").(string),
+		zoneid,
+	)
+
+	if checksum, ok := d.GetOk("checksum"); ok {
+		p.SetChecksum(checksum.(string))
+	}
+
+	if diskoffering, ok := d.GetOk("disk_offering"); ok {
+		// Retrieve the disk_offering ID
+		diskofferingid, e := retrieveID(cs, "disk_offering", diskoffering.(string))
+		if e != nil {
+			return e.Error()
+		}
+		// Set the disk_offering ID
+		p.SetDiskofferingid(diskofferingid)
+	}
+
+	// If there is a project supplied, we retrieve and set the project id
+	if err := setProjectid(p, cs, d); err != nil {
+		return err
+	}
+
+	// Upload the new volume
+	r, err := cs.Volume.UploadVolume(p)
+	if err != nil {
+		return fmt.Errorf("Error uploading the new disk %s: %s", name, err)
+	}
+
+	// Set the volume ID
+	d.SetId(r.Id)
+
+	// Wait until the volume is uploaded, or timeout with an error...
+	err = waitForReady(int64(d.Get("upload_timeout").(int)), "disk", func() (bool, error) {
+		v, _, err := cs.Volume.GetVolumeByID(
+			d.Id(),
+			cloudstack.WithProject(d.Get("project").(string)),
+		)
+		if err != nil {
+			return false, err
+		}
+
+		switch v.State {
+		case "Uploaded", "Ready":
+			return true, nil
+		case "UploadError", "UploadAbandoned":
+			return false, fmt.Errorf("Error uploading the new disk %s: %s", name, v.Status)
+		}
+
+		return false, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return resourceCloudStackDiskCreated(d, meta)
+}
+
+// resourceCloudStackDiskCreated finishes the creation of a new volume by
+// setting the tags, attaching it and migrating it when needed
+func resourceCloudStackDiskCreated(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	name := d.Get("name").(string)
+
 	// Set tags if necessary
-	err = setTags(cs, d, "Volume")
+	err := setTags(cs, d, "Volume")
 	if err != nil {
 		return fmt.Errorf("Error setting tags on the new disk %s: %s", name, err)
 	}
@@ -161,6 +307,11 @@ func resourceCloudStackDiskCreate(d *schema.ResourceData, meta interface{}) erro
 		// Set the additional partial
 	}
 
+	// Migrate the volume if it is not yet on the requested storage pool
+	if err := resourceCloudStackDiskMigrate(d, meta); err != nil {
+		return fmt.Errorf("Error migrating the new disk %s: %s", name, err)
+	}
+
 	return resourceCloudStackDiskRead(d, meta)
 }
 
@@ -191,6 +342,16 @@ func resourceCloudStackDiskRead(d *schema.ResourceData, meta interface{}) error
 	}
 	d.Set("tags", tags)
 
+	if v.Snapshotid != "" {
+		d.Set("snapshot_id", v.Snapshotid)
+	}
+
+	// A volume is only placed on a storage pool once it is attached, so keep
+	// the configured storage pool until then to apply it when attaching
+	if v.Storageid != "" {
+		d.Set("storage_pool_id", v.Storageid)
+	}
+
 	setValueOrID(d, "disk_offering", v.Diskofferingname, v.Diskofferingid)
 	setValueOrID(d, "project", v.Project, v.Projectid)
 	setValueOrID(d, "zone", v.Zonename, v.Zoneid)
@@ -208,6 +369,16 @@ func resourceCloudStackDiskUpdate(d *schema.ResourceData, meta interface{}) erro
 
 	name := d.Get("name").(string)
 
+	// Check if the revert trigger has changed and if so, revert the volume to
+	// the given snapshot before making any other changes
+	if d.HasChange("revert_to_snapshot") {
+		if snapshotid := d.Get("revert_to_snapshot").(string); snapshotid != "" {
+			if err := resourceCloudStackDiskRevert(d, meta, snapshotid); err != nil {
+				return fmt.Errorf("Error reverting disk %s to snapshot %s: %s", name, snapshotid, err)
+			}
+		}
+	}
+
 	if d.HasChange("disk_offering") || d.HasChange("size") {
 		if d.Get("reattach_on_change").(bool) {
 			// Detach the volume (re-attach is done at the end of this function)
@@ -216,34 +387,58 @@ func resourceCloudStackDiskUpdate(d *schema.ResourceData, meta interface{}) erro
 			}
 		}
 
-		// Create a new parameter struct
-		p := cs.Volume.NewResizeVolumeParams(d.Id())
-
 		// Retrieve the disk_offering ID
 		diskofferingid, e := retrieveID(cs, "disk_offering", d.Get("disk_offering").(string))
 		if e != nil {
 			return e.Error()
 		}
 
-		// Set the disk_offering ID
-		p.SetDiskofferingid(diskofferingid)
-
+		var size int64
 		if d.HasChange("size") {
-			// Set the size
-			p.SetSize(int64(d.Get("size").(int)))
+			size = int64(d.Get("size").(int))
 		}
 
-		// Set the shrink bit
-		p.SetShrinkok(d.Get("shrink_ok").(bool))
+		if d.HasChange("disk_offering") {
+			// Create a new parameter struct
+			p := cs.Volume.NewChangeOfferingForVolumeParams(diskofferingid, d.Id())
 
-		// Change the disk_offering
-		r, err := cs.Volume.ResizeVolume(p)
-		if err != nil {
-			return fmt.Errorf("Error changing disk offering/size for disk %s: %s", name, err)
+			if size != 0 {
+				// Set the size
+				p.SetSize(size)
+			}
+
+			// Set the shrink bit
+			p.SetShrinkok(d.Get("shrink_ok").(bool))
+
+			// Migrate the volume when the new disk offering needs another storage pool
+			p.SetAutomigrate(true)
+
+			// Change the disk_offering and size
+			r, err := cs.Volume.ChangeOfferingForVolume(p)
+			if err != nil {
+				return fmt.Errorf("Error changing disk offering for disk %s: %s", name, err)
+			}
+
+			// Update the volume ID and set partials
+			d.SetId(r.Id)
+		} else {
+			// Change the size
+			r, err := resizeVolume(cs, d.Id(), diskofferingid, size, d.Get("shrink_ok").(bool))
+			if err != nil {
+				return fmt.Errorf("Error changing size for disk %s: %s", name, err)
+			}
+
+			// Update the volume ID and set partials
+			d.SetId(r.Id)
 		}
+	}
 
-		// Update the volume ID and set partials
-		d.SetId(r.Id)
+	// A volume is only placed on a storage pool once it is attached for the
+	// first time, so check if a configured storage pool still needs to be
+	// applied after attaching the volume
+	placing, err := diskNeedsPlacement(d, meta)
+	if err != nil {
+		return err
 	}
 
 	// If the device ID changed, just detach here so we can re-attach the
@@ -270,6 +465,14 @@ func resourceCloudStackDiskUpdate(d *schema.ResourceData, meta interface{}) erro
 		}
 	}
 
+	// Check if the storage pool has changed or needs to be applied to a newly
+	// placed volume and if so, migrate the volume
+	if d.HasChange("storage_pool_id") || placing {
+		if err := resourceCloudStackDiskMigrate(d, meta); err != nil {
+			return fmt.Errorf("Error migrating disk %s: %s", name, err)
+		}
+	}
+
 	// Check is the tags have changed and if so, update the tags
 	if d.HasChange("tags") {
 		err := updateTags(cs, d, "Volume")
@@ -379,6 +582,150 @@ func resourceCloudStackDiskDetach(d *schema.ResourceData, meta interface{}) erro
 	return err
 }
 
+// diskNeedsPlacement returns true if a storage pool is configured for a volume
+// that is not yet placed on a storage pool
+func diskNeedsPlacement(d *schema.ResourceData, meta interface{}) (bool, error) {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	if d.GetRawConfig().GetAttr("storage_pool_id").IsNull() {
+		return false, nil
+	}
+
+	v, _, err := cs.Volume.GetVolumeByID(
+		d.Id(),
+		cloudstack.WithProject(d.Get("project").(string)),
+	)
+	if err != nil {
+		return false, err
+	}
+
+	return v.Storageid == "", nil
+}
+
+// resourceCloudStackDiskMigrate migrates the volume to the configured storage
+// pool. Volumes attached to a running virtual machine are live migrated.
+func resourceCloudStackDiskMigrate(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// The storage pool is computed, so only migrate when it is configured
+	if d.GetRawConfig().GetAttr("storage_pool_id").IsNull() {
+		return nil
+	}
+	storageid := d.Get("storage_pool_id").(string)
+
+	v, _, err := cs.Volume.GetVolumeByID(
+		d.Id(),
+		cloudstack.WithProject(d.Get("project").(string)),
+	)
+	if err != nil {
+		return err
+	}
+
+	// A volume that is not yet placed on a storage pool cannot be migrated,
+	// the storage pool is applied once the volume is attached
+	if v.Storageid == "" {
+		log.Printf("[DEBUG] Volume %s is not yet placed on a storage pool", d.Id())
+		return nil
+	}
+
+	if v.Storageid == storageid {
+		return nil
+	}
+
+	// Create a new parameter struct
+	p := cs.Volume.NewMigrateVolumeParams(storageid, d.Id())
+	p.SetLivemigrate(v.Virtualmachineid != "" && v.Vmstate == "Running")
+
+	log.Printf("[DEBUG] Migrating volume %s to storage pool %s", d.Id(), storageid)
+	r, err := cs.Volume.MigrateVolume(p)
+	if err != nil {
+		return err
+	}
+
+	// Update the volume ID
+	d.SetId(r.Id)
+
+	return nil
+}
+
+// resourceCloudStackDiskRevert reverts the volume to one of its snapshots. A
+// running virtual machine the volume is attached to is stopped before
+// reverting and started again afterwards.
+func resourceCloudStackDiskRevert(d *schema.ResourceData, meta interface{}, snapshotid string) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Make sure the snapshot is a snapshot of this volume
+	s, _, err := cs.Snapshot.GetSnapshotByID(
+		snapshotid,
+		cloudstack.WithProject(d.Get("project").(string)),
+	)
+	if err != nil {
+		return err
+	}
+
+	if s.Volumeid != d.Id() {
+		return fmt.Errorf("Snapshot %s is not a snapshot of disk %s", snapshotid, d.Id())
+	}
+
+	v, _, err := cs.Volume.GetVolumeByID(
+		d.Id(),
+		cloudstack.WithProject(d.Get("project").(string)),
+	)
+	if err != nil {
+		return err
+	}
+
+	restart := v.Virtualmachineid != "" && v.Vmstate == "Running"
+	if restart {
+		_, err := cs.VirtualMachine.StopVirtualMachine(
+			cs.VirtualMachine.NewStopVirtualMachineParams(v.Virtualmachineid))
+		if err != nil {
+			return fmt.Errorf("Error stopping virtual machine %s: %s", v.Virtualmachineid, err)
+		}
+	}
+
+	if _, err := cs.Snapshot.RevertSnapshot(cs.Snapshot.NewRevertSnapshotParams(snapshotid)); err != nil {
+		return err
+	}
+
+	if restart {
+		_, err := cs.VirtualMachine.StartVirtualMachine(
+			cs.VirtualMachine.NewStartVirtualMachineParams(v.Virtualmachineid))
+		if err != nil {
+			return fmt.Errorf("Error starting virtual machine %s: %s", v.Virtualmachineid, err)
+		}
+	}
+
+	return nil
+}
+
+// resizeVolume resizes the volume with the given ID. An empty disk offering
+// ID or a size of 0 leaves the current disk offering or size untouched.
+func resizeVolume(
+	cs *cloudstack.CloudStackClient,
+	id string,
+	diskofferingid string,
+	size int64,
+	shrinkok bool) (*cloudstack.ResizeVolumeResponse, error) {
+	// Create a new parameter struct
+	p := cs.Volume.NewResizeVolumeParams(id)
+
+	if diskofferingid != "" {
+		// Set the disk_offering ID
+		p.SetDiskofferingid(diskofferingid)
+	}
+
+	if size != 0 {
+		// Set the size
+		p.SetSize(size)
+	}
+
+	// Set the shrink bit
+	p.SetShrinkok(shrinkok)
+
+	return cs.Volume.ResizeVolume(p)
+}
+
 func isAttached(d *schema.ResourceData, meta interface{}) (bool, error) {
 	cs := meta.(*cloudstack.CloudStackClient)
 
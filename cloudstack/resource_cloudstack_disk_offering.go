@@ -20,32 +20,147 @@
 package cloudstack
 
 import (
+	"fmt"
 	"log"
+	"strings"
 
 	"github.com/apache/cloudstack-go/v2/cloudstack"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
+// The bytes and IOPS rate limits of a disk offering
+var diskOfferingRateLimits = []string{
+	"bytes_read_rate",
+	"bytes_read_rate_max",
+	"bytes_read_rate_max_length",
+	"bytes_write_rate",
+	"bytes_write_rate_max",
+	"bytes_write_rate_max_length",
+	"iops_read_rate",
+	"iops_read_rate_max",
+	"iops_read_rate_max_length",
+	"iops_write_rate",
+	"iops_write_rate_max",
+	"iops_write_rate_max_length",
+}
+
 func resourceCloudStackDiskOffering() *schema.Resource {
+	s := map[string]*schema.Schema{
+		"name": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"display_text": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"disk_size": {
+			Description: "The size of the disk offering in GB",
+			Type:        schema.TypeInt,
+			Optional:    true,
+			ForceNew:    true,
+		},
+		"customized": {
+			Description: "Whether the disk size can be chosen when creating a disk",
+			Type:        schema.TypeBool,
+			Optional:    true,
+			ForceNew:    true,
+			Default:     false,
+		},
+		"customized_iops": {
+			Description: "Whether the IOPS can be chosen when creating a disk",
+			Type:        schema.TypeBool,
+			Optional:    true,
+			ForceNew:    true,
+			Default:     false,
+		},
+		"min_iops": {
+			Description: "The minimum IOPS of the disk offering",
+			Type:        schema.TypeInt,
+			Optional:    true,
+			ForceNew:    true,
+		},
+		"max_iops": {
+			Description: "The maximum IOPS of the disk offering",
+			Type:        schema.TypeInt,
+			Optional:    true,
+			ForceNew:    true,
+		},
+		"hypervisor_snapshot_reserve": {
+			Description: "The hypervisor snapshot reserve space as a percentage of a volume",
+			Type:        schema.TypeInt,
+			Optional:    true,
+			ForceNew:    true,
+		},
+		"storage_type": {
+			Description:  "The storage type of the disk offering. Values are local and shared",
+			Type:         schema.TypeString,
+			Optional:     true,
+			ForceNew:     true,
+			Default:      "shared",
+			ValidateFunc: validation.StringInSlice([]string{"local", "shared"}, false),
+		},
+		"tags": {
+			Description: "The storage tags for this disk offering",
+			Type:        schema.TypeString,
+			Optional:    true,
+		},
+		"provisioning_type": {
+			Description:  "The provisioning type of the disk offering. Values are thin, sparse and fat",
+			Type:         schema.TypeString,
+			Optional:     true,
+			Computed:     true,
+			ForceNew:     true,
+			ValidateFunc: validation.StringInSlice([]string{"thin", "sparse", "fat"}, false),
+		},
+		"cache_mode": {
+			Description:  "The cache mode of the disk offering. Values are none, writeback and writethrough",
+			Type:         schema.TypeString,
+			Optional:     true,
+			Computed:     true,
+			ValidateFunc: validation.StringInSlice([]string{"none", "writeback", "writethrough"}, false),
+		},
+		"domain_ids": {
+			Description: "The IDs of the domains the disk offering is available in, or public when empty",
+			Type:        schema.TypeSet,
+			Optional:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+			Set:         schema.HashString,
+		},
+		"zone_ids": {
+			Description: "The IDs of the zones the disk offering is available in, or all zones when empty",
+			Type:        schema.TypeSet,
+			Optional:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+			Set:         schema.HashString,
+		},
+		"encrypt": {
+			Description: "Whether disks created with the disk offering are encrypted",
+			Type:        schema.TypeBool,
+			Optional:    true,
+			ForceNew:    true,
+			Default:     false,
+		},
+	}
+
+	for _, k := range diskOfferingRateLimits {
+		s[k] = &schema.Schema{
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validation.IntAtLeast(0),
+		}
+	}
+
 	return &schema.Resource{
 		Create: resourceCloudStackDiskOfferingCreate,
 		Read:   resourceCloudStackDiskOfferingRead,
 		Update: resourceCloudStackDiskOfferingUpdate,
 		Delete: resourceCloudStackDiskOfferingDelete,
-		Schema: map[string]*schema.Schema{
-			"name": {
-				Type:     schema.TypeString,
-				Required: true,
-			},
-			"display_text": {
-				Type:     schema.TypeString,
-				Required: true,
-			},
-			"disk_size": {
-				Type:     schema.TypeInt,
-				Required: true,
-			},
+		Importer: &schema.ResourceImporter{
+			StateContext: importStatePassthroughContext,
 		},
+		Schema: s,
 	}
 }
 
@@ -53,11 +168,76 @@ func resourceCloudStackDiskOfferingCreate(d *schema.ResourceData, meta interface
 	cs := meta.(*cloudstack.CloudStackClient)
 	name := d.Get("name").(string)
 	display_text := d.Get("display_text").(string)
-	disk_size := d.Get("disk_size").(int)
 
 	// Create a new parameter struct
-	p := cs.DiskOffering.NewCreateDiskOfferingParams(name, display_text)
-	p.SetDisksize(int64(disk_size))
+	p := cs.DiskOffering.NewCreateDiskOfferingParams(display_text, name)
+	if v, ok := d.GetOk("disk_size"); ok {
+		p.SetDisksize(int64(v.(int)))
+	}
+
+	p.SetCustomized(d.Get("customized").(bool))
+	p.SetCustomizediops(d.Get("customized_iops").(bool))
+
+	if v, ok := d.GetOk("min_iops"); ok {
+		p.SetMiniops(int64(v.(int)))
+	}
+
+	if v, ok := d.GetOk("max_iops"); ok {
+		p.SetMaxiops(int64(v.(int)))
+	}
+
+	if v, ok := d.GetOk("hypervisor_snapshot_reserve"); ok {
+		p.SetHypervisorsnapshotreserve(v.(int))
+	}
+
+	if v, ok := d.GetOk("storage_type"); ok {
+		p.SetStoragetype(v.(string))
+	}
+
+	if v, ok := d.GetOk("tags"); ok {
+		p.SetTags(v.(string))
+	}
+
+	if v, ok := d.GetOk("provisioning_type"); ok {
+		p.SetProvisioningtype(v.(string))
+	}
+
+	if v, ok := d.GetOk("cache_mode"); ok {
+		p.SetCachemode(v.(string))
+	}
+
+	if v, ok := d.GetOk("domain_ids"); ok {
+		p.SetDomainid(diskOfferingIDs(v.(*schema.Set)))
+	}
+
+	if v, ok := d.GetOk("zone_ids"); ok {
+		p.SetZoneid(diskOfferingIDs(v.(*schema.Set)))
+	}
+
+	if d.Get("encrypt").(bool) {
+		p.SetEncrypt(true)
+	}
+
+	rates := map[string]func(int64){
+		"bytes_read_rate":             p.SetBytesreadrate,
+		"bytes_read_rate_max":         p.SetBytesreadratemax,
+		"bytes_read_rate_max_length":  p.SetBytesreadratemaxlength,
+		"bytes_write_rate":            p.SetByteswriterate,
+		"bytes_write_rate_max":        p.SetByteswriteratemax,
+		"bytes_write_rate_max_length": p.SetByteswriteratemaxlength,
+		"iops_read_rate":              p.SetIopsreadrate,
+		"iops_read_rate_max":          p.SetIopsreadratemax,
+		"iops_read_rate_max_length":   p.SetIopsreadratemaxlength,
+		"iops_write_rate":             p.SetIopswriterate,
+		"iops_write_rate_max":         p.SetIopswriteratemax,
+		"iops_write_rate_max_length":  p.SetIopswriteratemaxlength,
+	}
+
+	for k, set := range rates {
+		if v, ok := d.GetOk(k); ok {
+			set(int64(v.(int)))
+		}
+	}
 
 	log.Printf("[DEBUG] Creating Disk Offering %s", name)
 	diskOff, err := cs.DiskOffering.CreateDiskOffering(p)
@@ -72,8 +252,169 @@ func resourceCloudStackDiskOfferingCreate(d *schema.ResourceData, meta interface
 	return resourceCloudStackDiskOfferingRead(d, meta)
 }
 
-func resourceCloudStackDiskOfferingRead(d *schema.ResourceData, meta interface{}) error { return nil }
+func resourceCloudStackDiskOfferingRead(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+	log.Printf("[DEBUG] Retrieving Disk Offering %s", d.Id())
 
-func resourceCloudStackDiskOfferingUpdate(d *schema.ResourceData, meta interface{}) error { return nil }
+	// Get the Disk Offering details
+	o, count, err := cs.DiskOffering.GetDiskOfferingByID(d.Id())
 
-func resourceCloudStackDiskOfferingDelete(d *schema.ResourceData, meta interface{}) error { return nil }
+	if err != nil {
+		if count == 0 {
+			log.Printf("[DEBUG] Disk Offering %s does no longer exist", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	fields := map[string]interface{}{
+		"name":                        o.Name,
+		"display_text":                o.Displaytext,
+		"disk_size":                   o.Disksize,
+		"customized":                  o.Iscustomized,
+		"customized_iops":             o.Iscustomizediops,
+		"min_iops":                    o.Miniops,
+		"max_iops":                    o.Maxiops,
+		"hypervisor_snapshot_reserve": o.Hypervisorsnapshotreserve,
+		"storage_type":                o.Storagetype,
+		"tags":                        o.Tags,
+		"provisioning_type":           o.Provisioningtype,
+		"cache_mode":                  o.CacheMode,
+		"domain_ids":                  diskOfferingIDSet(o.Domainid),
+		"zone_ids":                    diskOfferingIDSet(o.Zoneid),
+		"encrypt":                     o.Encrypt,
+		"bytes_read_rate":             o.DiskBytesReadRate,
+		"bytes_read_rate_max":         o.DiskBytesReadRateMax,
+		"bytes_read_rate_max_length":  o.DiskBytesReadRateMaxLength,
+		"bytes_write_rate":            o.DiskBytesWriteRate,
+		"bytes_write_rate_max":        o.DiskBytesWriteRateMax,
+		"bytes_write_rate_max_length": o.DiskBytesWriteRateMaxLength,
+		"iops_read_rate":              o.DiskIopsReadRate,
+		"iops_read_rate_max":          o.DiskIopsReadRateMax,
+		"iops_read_rate_max_length":   o.DiskIopsReadRateMaxLength,
+		"iops_write_rate":             o.DiskIopsWriteRate,
+		"iops_write_rate_max":         o.DiskIopsWriteRateMax,
+		"iops_write_rate_max_length":  o.DiskIopsWriteRateMaxLength,
+	}
+
+	for k, v := range fields {
+		d.Set(k, v)
+	}
+
+	return nil
+}
+
+func resourceCloudStackDiskOfferingUpdate(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	name := d.Get("name").(string)
+
+	// Create a new parameter struct
+	p := cs.DiskOffering.NewUpdateDiskOfferingParams(d.Id())
+
+	if d.HasChange("name") {
+		p.SetName(name)
+	}
+
+	if d.HasChange("display_text") {
+		p.SetDisplaytext(d.Get("display_text").(string))
+	}
+
+	if d.HasChange("tags") {
+		p.SetTags(d.Get("tags").(string))
+	}
+
+	if d.HasChange("cache_mode") {
+		p.SetCachemode(d.Get("cache_mode").(string))
+	}
+
+	// An empty list of domains makes the disk offering public
+	if d.HasChange("domain_ids") {
+		domainids := "public"
+		if ids := diskOfferingIDs(d.Get("domain_ids").(*schema.Set)); len(ids) > 0 {
+			domainids = strings.Join(ids, ",")
+		}
+		p.SetDomainid(domainids)
+	}
+
+	// An empty list of zones makes the disk offering available in all zones
+	if d.HasChange("zone_ids") {
+		zoneids := "all"
+		if ids := diskOfferingIDs(d.Get("zone_ids").(*schema.Set)); len(ids) > 0 {
+			zoneids = strings.Join(ids, ",")
+		}
+		p.SetZoneid(zoneids)
+	}
+
+	rates := map[string]func(int64){
+		"bytes_read_rate":             p.SetBytesreadrate,
+		"bytes_read_rate_max":         p.SetBytesreadratemax,
+		"bytes_read_rate_max_length":  p.SetBytesreadratemaxlength,
+		"bytes_write_rate":            p.SetByteswriterate,
+		"bytes_write_rate_max":        p.SetByteswriteratemax,
+		"bytes_write_rate_max_length": p.SetByteswriteratemaxlength,
+		"iops_read_rate":              p.SetIopsreadrate,
+		"iops_read_rate_max":          p.SetIopsreadratemax,
+		"iops_read_rate_max_length":   p.SetIopsreadratemaxlength,
+		"iops_write_rate":             p.SetIopswriterate,
+		"iops_write_rate_max":         p.SetIopswriteratemax,
+		"iops_write_rate_max_length":  p.SetIopswriteratemaxlength,
+	}
+
+	for k, set := range rates {
+		if d.HasChange(k) {
+			set(int64(d.Get(k).(int)))
+		}
+	}
+
+	log.Printf("[DEBUG] Updating Disk Offering %s", name)
+	if _, err := cs.DiskOffering.UpdateDiskOffering(p); err != nil {
+		return fmt.Errorf("Error updating disk offering %s: %s", name, err)
+	}
+
+	return resourceCloudStackDiskOfferingRead(d, meta)
+}
+
+func resourceCloudStackDiskOfferingDelete(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Create a new parameter struct
+	p := cs.DiskOffering.NewDeleteDiskOfferingParams(d.Id())
+
+	log.Printf("[INFO] Deleting Disk Offering: %s", d.Id())
+	if _, err := cs.DiskOffering.DeleteDiskOffering(p); err != nil {
+		// This is a very poor way to be told the ID does no longer exist :(
+		if strings.Contains(err.Error(), fmt.Sprintf(
+			"Invalid parameter id value=%s due to incorrect long value format, "+
+				"or entity does not exist", d.Id())) {
+			return nil
+		}
+
+		return fmt.Errorf("Error deleting Disk Offering %s: %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+// diskOfferingIDs returns the IDs of the set as a list of strings
+func diskOfferingIDs(s *schema.Set) []string {
+	ids := make([]string, 0, s.Len())
+	for _, id := range s.List() {
+		ids = append(ids, id.(string))
+	}
+
+	return ids
+}
+
+// diskOfferingIDSet returns a set of the comma separated IDs returned by the API
+func diskOfferingIDSet(ids string) *schema.Set {
+	s := &schema.Set{F: schema.HashString}
+	for _, id := range strings.Split(ids, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			s.Add(id)
+		}
+	}
+
+	return s
+}
@@ -21,6 +21,7 @@ package cloudstack
 
 import (
 	"fmt"
+	"os"
 	"testing"
 
 	"github.com/apache/cloudstack-go/v2/cloudstack"
@@ -98,6 +99,106 @@ func TestAccCloudStackDisk_deviceID(t *testing.T) {
 						"cloudstack_disk.foo", "device_id", "4"),
 				),
 			},
+
+			{
+				// Changing the disk offering without a configured storage pool
+				// must keep the pool the volume is automatically migrated to
+				Config: testAccCloudStackDisk_deviceIDResize,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackDiskExists(
+						"cloudstack_disk.foo", &disk),
+					testAccCheckCloudStackDiskResized(&disk),
+					testAccCheckCloudStackDiskStoragePool(
+						"cloudstack_disk.foo", &disk),
+					resource.TestCheckResourceAttr(
+						"cloudstack_disk.foo", "device_id", "4"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudStackDisk_fromSnapshot(t *testing.T) {
+	var disk cloudstack.Volume
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackDiskDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackDisk_fromSnapshot,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackDiskExists(
+						"cloudstack_disk.bar", &disk),
+					resource.TestCheckResourceAttrPair(
+						"cloudstack_disk.bar", "snapshot_id", "cloudstack_snapshot.foo", "id"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_disk.bar", "disk_offering", "Small"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudStackDisk_storagePool(t *testing.T) {
+	var disk cloudstack.Volume
+
+	// The simulator has a single storage pool per cluster, so the pool to
+	// migrate to needs to be supplied
+	storageid := os.Getenv("CLOUDSTACK_STORAGE_POOL_ID")
+	if storageid == "" {
+		t.Skip("This test requires CLOUDSTACK_STORAGE_POOL_ID to be set")
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackDiskDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackDisk_deviceID,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackDiskExists(
+						"cloudstack_disk.foo", &disk),
+					resource.TestCheckResourceAttrSet(
+						"cloudstack_disk.foo", "storage_pool_id"),
+				),
+			},
+
+			{
+				Config: testAccCloudStackDisk_storagePool(storageid),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackDiskExists(
+						"cloudstack_disk.foo", &disk),
+					resource.TestCheckResourceAttr(
+						"cloudstack_disk.foo", "storage_pool_id", storageid),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudStackDisk_upload(t *testing.T) {
+	var disk cloudstack.Volume
+
+	if cloudStackVolumeURL == "" {
+		t.Skip("This test requires an upload URL")
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackDiskDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackDisk_upload,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackDiskExists(
+						"cloudstack_disk.foo", &disk),
+					testAccCheckCloudStackDiskUploaded(&disk),
+				),
+			},
 		},
 	})
 }
@@ -116,7 +217,7 @@ func TestAccCloudStackDisk_import(t *testing.T) {
 				ResourceName:            "cloudstack_disk.foo",
 				ImportState:             true,
 				ImportStateVerify:       true,
-				ImportStateVerifyIgnore: []string{"shrink_ok", "reattach_on_change"},
+				ImportStateVerifyIgnore: []string{"shrink_ok", "reattach_on_change", "upload_timeout"},
 			},
 		},
 	})
@@ -179,6 +280,35 @@ func testAccCheckCloudStackDiskResized(
 	}
 }
 
+func testAccCheckCloudStackDiskStoragePool(
+	n string, disk *cloudstack.Volume) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.Attributes["storage_pool_id"] != disk.Storageid {
+			return fmt.Errorf(
+				"Bad storage pool: %s (expected %s)", rs.Primary.Attributes["storage_pool_id"], disk.Storageid)
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckCloudStackDiskUploaded(
+	disk *cloudstack.Volume) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+
+		if disk.State != "Uploaded" && disk.State != "Ready" {
+			return fmt.Errorf("Bad state: %s", disk.State)
+		}
+
+		return nil
+	}
+}
+
 func testAccCheckCloudStackDiskDestroy(s *terraform.State) error {
 	cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
 
@@ -252,3 +382,90 @@ resource "cloudstack_disk" "foo" {
   virtual_machine_id = cloudstack_instance.foobar.id
   zone = cloudstack_instance.foobar.zone
 }`
+
+const testAccCloudStackDisk_deviceIDResize = `
+resource "cloudstack_network" "foo" {
+  name = "terraform-network"
+  display_text = "terraform-network"
+  cidr = "10.1.1.0/24"
+  network_offering = "DefaultIsolatedNetworkOfferingWithSourceNatService"
+  zone = "Sandbox-simulator"
+}
+
+resource "cloudstack_instance" "foobar" {
+  name = "terraform-test"
+  display_name = "terraform"
+  service_offering= "Small Instance"
+  network_id = cloudstack_network.foo.id
+  template = "CentOS 5.6 (64-bit) no GUI (Simulator)"
+  zone = cloudstack_network.foo.zone
+  expunge = true
+}
+
+resource "cloudstack_disk" "foo" {
+  name = "terraform-disk"
+  attach = true
+  device_id = 4
+  disk_offering = "Medium"
+  virtual_machine_id = cloudstack_instance.foobar.id
+  zone = cloudstack_instance.foobar.zone
+}`
+
+const testAccCloudStackDisk_fromSnapshot = `
+resource "cloudstack_disk" "foo" {
+  name = "terraform-disk"
+  attach = false
+  disk_offering = "Small"
+  zone = "Sandbox-simulator"
+}
+
+resource "cloudstack_snapshot" "foo" {
+  volume_id = cloudstack_disk.foo.id
+}
+
+resource "cloudstack_disk" "bar" {
+  name = "terraform-disk-restored"
+  attach = false
+  snapshot_id = cloudstack_snapshot.foo.id
+  zone = "Sandbox-simulator"
+}`
+
+func testAccCloudStackDisk_storagePool(storageid string) string {
+	return fmt.Sprintf(`
+resource "cloudstack_network" "foo" {
+  name = "terraform-network"
+  display_text = "terraform-network"
+  cidr = "10.1.1.0/24"
+  network_offering = "DefaultIsolatedNetworkOfferingWithSourceNatService"
+  zone = "Sandbox-simulator"
+}
+
+resource "cloudstack_instance" "foobar" {
+  name = "terraform-test"
+  display_name = "terraform"
+  service_offering= "Small Instance"
+  network_id = cloudstack_network.foo.id
+  template = "CentOS 5.6 (64-bit) no GUI (Simulator)"
+  zone = cloudstack_network.foo.zone
+  expunge = true
+}
+
+resource "cloudstack_disk" "foo" {
+  name = "terraform-disk"
+  attach = true
+  device_id = 4
+  disk_offering = "Small"
+  storage_pool_id = "%s"
+  virtual_machine_id = cloudstack_instance.foobar.id
+  zone = cloudstack_instance.foobar.zone
+}`, storageid)
+}
+
+var testAccCloudStackDisk_upload = fmt.Sprintf(`
+resource "cloudstack_disk" "foo" {
+  name = "terraform-disk-uploaded"
+  attach = false
+  url = "%s"
+  format = "VHD"
+  zone = "Sandbox-simulator"
+}`, cloudStackVolumeURL)
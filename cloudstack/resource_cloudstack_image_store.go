@@ -0,0 +1,230 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceCloudStackImageStore() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceCloudStackImageStoreCreate,
+		Read:   resourceCloudStackImageStoreRead,
+		Update: resourceCloudStackImageStoreUpdate,
+		Delete: resourceCloudStackImageStoreDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: importStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+
+			"storage_provider": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"url": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"zone": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"details": {
+				Type:             schema.TypeMap,
+				Optional:         true,
+				ForceNew:         true,
+				Sensitive:        true,
+				Elem:             &schema.Schema{Type: schema.TypeString},
+				DiffSuppressFunc: suppressImportedImageStoreDetailsDiff,
+			},
+
+			"read_only": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+
+			"protocol": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"scope": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceCloudStackImageStoreCreate(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Create a new parameter struct
+	p := cs.ImageStore.NewAddImageStoreParams(d.Get("storage_provider").(string))
+
+	if name, ok := d.GetOk("name"); ok {
+		p.SetName(name.(string))
+	}
+
+	if url, ok := d.GetOk("url"); ok {
+		p.SetUrl(url.(string))
+	}
+
+	if zone, ok := d.GetOk("zone"); ok {
+		// Retrieve the zone ID
+		zoneid, e := retrieveID(cs, "zone", zone.(string))
+		if e != nil {
+			return e.Error()
+		}
+		p.SetZoneid(zoneid)
+	}
+
+	if details, ok := d.GetOk("details"); ok {
+		p.SetDetails(stringMapFromSchema(details.(map[string]interface{})))
+	}
+
+	log.Printf("[DEBUG] Adding image store %s", d.Get("name").(string))
+	r, err := cs.ImageStore.AddImageStore(p)
+	if err != nil {
+		return fmt.Errorf("Error adding image store: %s", err)
+	}
+
+	d.SetId(r.Id)
+
+	if d.Get("read_only").(bool) {
+		if err := updateImageStore(cs, d); err != nil {
+			return err
+		}
+	}
+
+	return resourceCloudStackImageStoreRead(d, meta)
+}
+
+func resourceCloudStackImageStoreRead(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Get the image store details
+	s, count, err := cs.ImageStore.GetImageStoreByID(d.Id())
+	if err != nil {
+		if count == 0 {
+			log.Printf("[DEBUG] Image store %s does no longer exist", d.Get("name").(string))
+			d.SetId("")
+			return nil
+		}
+
+		return err
+	}
+
+	d.Set("name", s.Name)
+	d.Set("storage_provider", s.Providername)
+	d.Set("url", s.Url)
+	d.Set("read_only", s.Readonly)
+	d.Set("protocol", s.Protocol)
+	d.Set("scope", s.Scope)
+
+	if s.Zoneid != "" {
+		setValueOrID(d, "zone", s.Zonename, s.Zoneid)
+	}
+
+	return nil
+}
+
+func resourceCloudStackImageStoreUpdate(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	if d.HasChanges("name", "read_only") {
+		if err := updateImageStore(cs, d); err != nil {
+			return err
+		}
+	}
+
+	return resourceCloudStackImageStoreRead(d, meta)
+}
+
+func resourceCloudStackImageStoreDelete(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Create a new parameter struct
+	p := cs.ImageStore.NewDeleteImageStoreParams(d.Id())
+
+	log.Printf("[INFO] Deleting image store: %s", d.Get("name").(string))
+	if _, err := cs.ImageStore.DeleteImageStore(p); err != nil {
+		// This is a very poor way to be told the ID does no longer exist :(
+		if strings.Contains(err.Error(), fmt.Sprintf(
+			"Invalid parameter id value=%s due to incorrect long value format, "+
+				"or entity does not exist", d.Id())) {
+			return nil
+		}
+
+		return fmt.Errorf("Error deleting image store %s: %s", d.Get("name").(string), err)
+	}
+
+	return nil
+}
+
+// suppressImportedImageStoreDetailsDiff suppresses the diff of the details of
+// an existing image store without details in its state. The details are not
+// returned by the API, so this prevents replacing an imported image store.
+func suppressImportedImageStoreDetailsDiff(k, old, new string, d *schema.ResourceData) bool {
+	if d.Id() == "" {
+		return false
+	}
+
+	o, _ := d.GetChange("details")
+	return len(o.(map[string]interface{})) == 0
+}
+
+// updateImageStore updates the name and read-only flag of an image store. The
+// read-only flag is always sent, as older CloudStack versions require it.
+func updateImageStore(cs *cloudstack.CloudStackClient, d *schema.ResourceData) error {
+	// Create a new parameter struct
+	p := cs.ImageStore.NewUpdateImageStoreParams(d.Id())
+	p.SetReadonly(d.Get("read_only").(bool))
+
+	if d.HasChange("name") && d.Get("name").(string) != "" {
+		p.SetName(d.Get("name").(string))
+	}
+
+	log.Printf("[DEBUG] Updating image store %s", d.Id())
+	if _, err := cs.ImageStore.UpdateImageStore(p); err != nil {
+		return fmt.Errorf("Error updating image store %s: %s", d.Id(), err)
+	}
+
+	return nil
+}
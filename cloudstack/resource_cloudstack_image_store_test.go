@@ -0,0 +1,145 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccCloudStackImageStore_basic(t *testing.T) {
+	var store cloudstack.ImageStore
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackImageStoreDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackImageStore_basic(false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackImageStoreExists(
+						"cloudstack_image_store.foo", &store),
+					resource.TestCheckResourceAttr(
+						"cloudstack_image_store.foo", "name", "terraform-image-store"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_image_store.foo", "storage_provider", "NFS"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_image_store.foo", "protocol", "nfs"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_image_store.foo", "read_only", "false"),
+				),
+			},
+
+			{
+				Config: testAccCloudStackImageStore_basic(true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackImageStoreExists(
+						"cloudstack_image_store.foo", &store),
+					resource.TestCheckResourceAttr(
+						"cloudstack_image_store.foo", "read_only", "true"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudStackImageStore_import(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackImageStoreDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackImageStore_basic(false),
+			},
+
+			{
+				ResourceName:      "cloudstack_image_store.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckCloudStackImageStoreExists(
+	n string, store *cloudstack.ImageStore) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No image store ID is set")
+		}
+
+		cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+		i, _, err := cs.ImageStore.GetImageStoreByID(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		if i.Id != rs.Primary.ID {
+			return fmt.Errorf("Image store not found")
+		}
+
+		*store = *i
+
+		return nil
+	}
+}
+
+func testAccCheckCloudStackImageStoreDestroy(s *terraform.State) error {
+	cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "cloudstack_image_store" {
+			continue
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No image store ID is set")
+		}
+
+		_, _, err := cs.ImageStore.GetImageStoreByID(rs.Primary.ID)
+		if err == nil {
+			return fmt.Errorf("Image store %s still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+func testAccCloudStackImageStore_basic(readOnly bool) string {
+	return fmt.Sprintf(`
+resource "cloudstack_image_store" "foo" {
+  name = "terraform-image-store"
+  storage_provider = "NFS"
+  url = "nfs://10.147.28.6/export/home/sandbox/terraform-secondary"
+  zone = "Sandbox-simulator"
+  read_only = %t
+}`, readOnly)
+}
@@ -26,10 +26,17 @@ import (
 	"encoding/hex"
 	"fmt"
 	"log"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/apache/cloudstack-go/v2/cloudstack"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceCloudStackInstance() *schema.Resource {
@@ -42,6 +49,21 @@ func resourceCloudStackInstance() *schema.Resource {
 			StateContext: resourceCloudStackInstanceImportContext,
 		},
 
+		CustomizeDiff: resourceCloudStackInstanceCustomizeDiff,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(15 * time.Minute),
+		},
+
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Type:    resourceCloudStackInstanceV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceCloudStackInstanceStateUpgradeV0,
+				Version: 0,
+			},
+		},
+
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:     schema.TypeString,
@@ -87,18 +109,103 @@ func resourceCloudStackInstance() *schema.Resource {
 			},
 
 			"template": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{"template", "iso", "volume_id", "snapshot_id"},
+			},
+
+			"iso": {
 				Type:     schema.TypeString,
-				Required: true,
+				Optional: true,
 				ForceNew: true,
 			},
 
-			"root_disk_size": {
-				Type:     schema.TypeInt,
+			"volume_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"snapshot_id": {
+				Type:     schema.TypeString,
 				Optional: true,
-				Computed: true,
 				ForceNew: true,
 			},
 
+			"hypervisor": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressCaseDiff,
+			},
+
+			"root_disk_offering": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"volume_id", "snapshot_id"},
+			},
+
+			"root_disk_min_iops": {
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"volume_id", "snapshot_id"},
+			},
+
+			"root_disk_max_iops": {
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"volume_id", "snapshot_id"},
+			},
+
+			"data_disk": {
+				Type:          schema.TypeList,
+				Optional:      true,
+				ForceNew:      true,
+				MaxItems:      1,
+				ConflictsWith: []string{"iso", "volume_id", "snapshot_id"},
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+
+						"disk_offering": {
+							Type:     schema.TypeString,
+							Required: true,
+							ForceNew: true,
+						},
+
+						"size": {
+							Type:     schema.TypeInt,
+							Optional: true,
+							Computed: true,
+							ForceNew: true,
+						},
+					},
+				},
+			},
+
+			"root_disk_size": {
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"volume_id", "snapshot_id"},
+			},
+
+			"root_disk_shrink_ok": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+
 			"group": {
 				Type:     schema.TypeString,
 				Optional: true,
@@ -124,7 +231,6 @@ func resourceCloudStackInstance() *schema.Resource {
 			"security_group_ids": {
 				Type:          schema.TypeSet,
 				Optional:      true,
-				ForceNew:      true,
 				Elem:          &schema.Schema{Type: schema.TypeString},
 				Set:           schema.HashString,
 				ConflictsWith: []string{"security_group_names"},
@@ -133,7 +239,6 @@ func resourceCloudStackInstance() *schema.Resource {
 			"security_group_names": {
 				Type:          schema.TypeSet,
 				Optional:      true,
-				ForceNew:      true,
 				Elem:          &schema.Schema{Type: schema.TypeString},
 				Set:           schema.HashString,
 				ConflictsWith: []string{"security_group_ids"},
@@ -166,16 +271,45 @@ func resourceCloudStackInstance() *schema.Resource {
 			},
 
 			"host_id": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				DiffSuppressFunc: suppressUnplacedInstanceDiff,
 			},
 
 			"cluster_id": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				DiffSuppressFunc: suppressUnplacedInstanceDiff,
 			},
 
 			"uefi": {
+				Type:          schema.TypeBool,
+				Optional:      true,
+				Deprecated:    "Use boot_type and boot_mode instead",
+				ConflictsWith: []string{"boot_type", "boot_mode"},
+			},
+
+			"boot_type": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ForceNew:         true,
+				ValidateFunc:     validation.StringInSlice([]string{"BIOS", "UEFI"}, true),
+				DiffSuppressFunc: suppressCaseDiff,
+			},
+
+			"boot_mode": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ForceNew:         true,
+				ValidateFunc:     validation.StringInSlice([]string{"Legacy", "Secure"}, true),
+				DiffSuppressFunc: suppressCaseDiff,
+			},
+
+			"boot_into_setup": {
 				Type:     schema.TypeBool,
 				Optional: true,
 				Default:  false,
@@ -189,8 +323,9 @@ func resourceCloudStackInstance() *schema.Resource {
 			},
 
 			"user_data": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"user_data_id"},
 				StateFunc: func(v interface{}) string {
 					switch v.(type) {
 					case string:
@@ -202,11 +337,49 @@ func resourceCloudStackInstance() *schema.Resource {
 				},
 			},
 
+			"user_data_id": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"user_data"},
+			},
+
+			"user_data_details": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+
+			"pgp_key": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			"password_reset_trigger": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				RequiredWith: []string{"pgp_key"},
+			},
+
+			"encrypted_password": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"pgp_key_fingerprint": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
 			"details": {
 				Type:     schema.TypeMap,
 				Optional: true,
 			},
 
+			"extra_config": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
 			"properties": {
 				Type:     schema.TypeMap,
 				Optional: true,
@@ -224,8 +397,10 @@ func resourceCloudStackInstance() *schema.Resource {
 			},
 
 			"pod_id": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				DiffSuppressFunc: suppressUnplacedInstanceDiff,
 			},
 
 			"tags": tagsSchema(),
@@ -255,172 +430,94 @@ func resourceCloudStackInstanceCreate(d *schema.ResourceData, meta interface{})
 		return err
 	}
 
-	// Retrieve the template ID
-	templateid, e := retrieveTemplateID(cs, zone.Id, d.Get("template").(string))
-	if e != nil {
-		return e.Error()
-	}
-
-	// Create a new parameter struct
-	p := cs.VirtualMachine.NewDeployVirtualMachineParams(serviceofferingid, templateid, zone.Id)
-	p.SetStartvm(d.Get("start_vm").(bool))
-	vmDetails := make(map[string]string)
-	if details, ok := d.GetOk("details"); ok {
-		for k, v := range details.(map[string]interface{}) {
-			vmDetails[k] = v.(string)
-		}
-		p.SetDetails(vmDetails)
-	}
-
-	// Set VM Properties
-	vmProperties := make(map[string]string)
-	if properties, ok := d.GetOk("properties"); ok {
-		for k, v := range properties.(map[string]interface{}) {
-			vmProperties[k] = v.(string)
-		}
-		p.SetProperties(vmProperties)
-	}
-
-	// SetNicNetworkList
-	if nicnetworklist, ok := d.GetOk("nicnetworklist"); ok {
-		nicNetworkDetails := []map[string]string{
-			{
-				"nic":     nicnetworklist.(map[string]interface{})["nic"].(string),
-				"network": nicnetworklist.(map[string]interface{})["network"].(string),
-			},
+	// Deploying from an existing volume or snapshot is not supported by the
+	// deploy parameters of the client, so it uses a custom request instead
+	_, hasVolume := d.GetOk("volume_id")
+	_, hasSnapshot := d.GetOk("snapshot_id")
+	if hasVolume || hasSnapshot {
+		r, err := deployVirtualMachineFromVolume(d, meta, serviceofferingid, zone)
+		if err != nil {
+			return fmt.Errorf("Error creating the new instance %s: %s", d.Get("name").(string), err)
 		}
-		p.SetNicnetworklist(nicNetworkDetails)
-	}
-
-	// Set the name
-	name, hasName := d.GetOk("name")
-	if hasName {
-		p.SetName(name.(string))
-	}
 
-	// Set the display name
-	if displayname, ok := d.GetOk("display_name"); ok {
-		p.SetDisplayname(displayname.(string))
-	} else if hasName {
-		p.SetDisplayname(name.(string))
-	}
-
-	// If there is a root_disk_size supplied, add it to the parameter struct
-	if rootdisksize, ok := d.GetOk("root_disk_size"); ok {
-		p.SetRootdisksize(int64(rootdisksize.(int)))
-	}
-
-	if d.Get("uefi").(bool) {
-		p.SetBoottype("UEFI")
-		p.SetBootmode("Legacy")
+		return resourceCloudStackInstanceDeployed(d, meta, r)
 	}
 
-	if zone.Networktype == "Advanced" {
-		// Set the default network ID
-		p.SetNetworkids([]string{d.Get("network_id").(string)})
+	var templateid string
+	if iso, ok := d.GetOk("iso"); ok {
+		// Retrieve the ISO ID, which is deployed as if it was a template
+		templateid, e = retrieveIsoID(cs, zone.Id, iso.(string))
+	} else {
+		// Retrieve the template ID
+		templateid, e = retrieveTemplateID(cs, zone.Id, d.Get("template").(string))
 	}
-
-	// If there is a ipaddres supplied, add it to the parameter struct
-	if ipaddress, ok := d.GetOk("ip_address"); ok {
-		p.SetIpaddress(ipaddress.(string))
+	if e != nil {
+		return e.Error()
 	}
 
-	// If there is a group supplied, add it to the parameter struct
-	if group, ok := d.GetOk("group"); ok {
-		p.SetGroup(group.(string))
-	}
+	// Create a new parameter struct
+	p := cs.VirtualMachine.NewDeployVirtualMachineParams(serviceofferingid, templateid, zone.Id)
 
-	// If there are affinity group IDs supplied, add them to the parameter struct
-	if agIDs := d.Get("affinity_group_ids").(*schema.Set); agIDs.Len() > 0 {
-		var groups []string
-		for _, group := range agIDs.List() {
-			groups = append(groups, group.(string))
+	// An ISO needs a disk offering for the root disk the ISO is installed on,
+	// the hypervisor to deploy to is set with the shared parameters
+	if _, ok := d.GetOk("iso"); ok {
+		diskofferingid, e := retrieveID(cs, "disk_offering", d.Get("root_disk_offering").(string))
+		if e != nil {
+			return e.Error()
 		}
-		p.SetAffinitygroupids(groups)
-	}
-
-	// If there are affinity group names supplied, add them to the parameter struct
-	if agNames := d.Get("affinity_group_names").(*schema.Set); agNames.Len() > 0 {
-		var groups []string
-		for _, group := range agNames.List() {
-			groups = append(groups, group.(string))
+		p.SetDiskofferingid(diskofferingid)
+	} else if rootdiskoffering, ok := d.GetOk("root_disk_offering"); ok {
+		// Override the disk offering of the service offering for the root disk
+		diskofferingid, e := retrieveID(cs, "disk_offering", rootdiskoffering.(string))
+		if e != nil {
+			return e.Error()
 		}
-		p.SetAffinitygroupnames(groups)
+		p.SetOverridediskofferingid(diskofferingid)
 	}
 
-	// If there are security group IDs supplied, add them to the parameter struct
-	if sgIDs := d.Get("security_group_ids").(*schema.Set); sgIDs.Len() > 0 {
-		var groups []string
-		for _, group := range sgIDs.List() {
-			groups = append(groups, group.(string))
+	// Set the disk offering and size of the data disk to create
+	if _, ok := d.GetOk("data_disk"); ok {
+		diskofferingid, e := retrieveID(cs, "disk_offering", d.Get("data_disk.0.disk_offering").(string))
+		if e != nil {
+			return e.Error()
 		}
-		p.SetSecuritygroupids(groups)
-	}
+		p.SetDiskofferingid(diskofferingid)
 
-	// If there are security group names supplied, add them to the parameter struct
-	if sgNames := d.Get("security_group_names").(*schema.Set); sgNames.Len() > 0 {
-		var groups []string
-		for _, group := range sgNames.List() {
-			groups = append(groups, group.(string))
+		if size, ok := d.GetOk("data_disk.0.size"); ok {
+			p.SetSize(int64(size.(int)))
 		}
-		p.SetSecuritygroupnames(groups)
 	}
 
-	// If there is a project supplied, we retrieve and set the project id
-	if err := setProjectid(p, cs, d); err != nil {
+	// Set the parameters shared with deploying from a volume
+	if err := setInstanceDeployParams(cs, d, p, zone); err != nil {
 		return err
 	}
 
-	// If a keypair is supplied, add it to the parameter struct
-	if keypair, ok := d.GetOk("keypair"); ok {
-		p.SetKeypair(keypair.(string))
-	}
-
-	if keypairs, ok := d.GetOk("keypairs"); ok {
-		var keypairStrings []string
-		for _, kp := range keypairs.([]interface{}) {
-			keypairStrings = append(keypairStrings, fmt.Sprintf("%v", kp))
-		}
-		p.SetKeypairs(keypairStrings)
-	}
-
-	// If a host_id is supplied, add it to the parameter struct
-
-	if hostid, ok := d.GetOk("host_id"); ok {
-		p.SetHostid(hostid.(string))
-	}
-
-	// If a pod_id is supplied, add it to the parameter struct
-
-	if podid, ok := d.GetOk("pod_id"); ok {
-		p.SetPodid(podid.(string))
-	}
-
-	// If a cluster_id is supplied, add it to the parameter struct
-
-	if clusterid, ok := d.GetOk("cluster_id"); ok {
-		p.SetClusterid(clusterid.(string))
-	}
-
-	if userData, ok := d.GetOk("user_data"); ok {
-		ud, err := getUserData(userData.(string))
-		if err != nil {
-			return err
-		}
-		p.SetUserdata(ud)
-	}
-
 	// Create the new instance
 	r, err := cs.VirtualMachine.DeployVirtualMachine(p)
 	if err != nil {
-		return fmt.Errorf("Error creating the new instance %s: %s", name, err)
+		return fmt.Errorf("Error creating the new instance %s: %s", d.Get("name").(string), err)
 	}
 
+	return resourceCloudStackInstanceDeployed(d, meta, r)
+}
+
+// resourceCloudStackInstanceDeployed finishes the creation of a newly
+// deployed instance
+func resourceCloudStackInstanceDeployed(
+	d *schema.ResourceData, meta interface{}, r *cloudstack.DeployVirtualMachineResponse) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
 	d.SetId(r.Id)
 
+	// Store the generated password encrypted with the PGP key, if any
+	if err := setEncryptedPassword(d, r.Password); err != nil {
+		return fmt.Errorf("Error encrypting the password of the new instance %s: %s", r.Name, err)
+	}
+
 	// Set tags if necessary
-	if err = setTags(cs, d, "userVm"); err != nil {
-		return fmt.Errorf("Error setting tags on the new instance %s: %s", name, err)
+	if err := setTags(cs, d, "userVm"); err != nil {
+		return fmt.Errorf("Error setting tags on the new instance %s: %s", r.Name, err)
 	}
 
 	// Set the connection info for any configured provisioners
@@ -455,6 +552,15 @@ func resourceCloudStackInstanceRead(d *schema.ResourceData, meta interface{}) er
 	d.Set("display_name", vm.Displayname)
 	d.Set("group", vm.Group)
 
+	// Only refresh registered user data that is configured, as instances
+	// deployed from a template with linked user data use that user data
+	if d.Get("user_data_id").(string) != "" {
+		d.Set("user_data_id", vm.Userdataid)
+	}
+
+	d.Set("boot_type", vm.Boottype)
+	d.Set("boot_mode", vm.Bootmode)
+
 	// In some rare cases (when destroying a machine fails) it can happen that
 	// an instance does not have any attached NIC anymore.
 	if len(vm.Nic) > 0 {
@@ -466,22 +572,24 @@ func resourceCloudStackInstanceRead(d *schema.ResourceData, meta interface{}) er
 		}
 	}
 
-	// Create a new param struct.
-	p := cs.Volume.NewListVolumesParams()
-	p.SetType("ROOT")
-	p.SetVirtualmachineid(d.Id())
-
 	// Get the root disk of the instance.
-	l, err := cs.Volume.ListVolumes(p)
+	rootVolume, err := getRootVolume(cs, d.Id())
 	if err != nil {
 		return err
 	}
 
 	// If we found the root disk, then update its size.
-	if len(l.Volumes) != 1 {
+	if rootVolume == nil {
 		log.Printf("[DEBUG] Failed to find root disk of instance: %s", vm.Name)
 	} else {
-		d.Set("root_disk_size", l.Volumes[0].Size>>30) // B to GiB
+		d.Set("root_disk_size", rootVolume.Size>>30) // B to GiB
+		d.Set("root_disk_min_iops", rootVolume.Miniops)
+		d.Set("root_disk_max_iops", rootVolume.Maxiops)
+		setValueOrID(d, "root_disk_offering", rootVolume.Diskofferingname, rootVolume.Diskofferingid)
+	}
+
+	if err := readInstanceDataDisk(d, meta); err != nil {
+		return err
 	}
 
 	if _, ok := d.GetOk("affinity_group_ids"); ok {
@@ -516,10 +624,48 @@ func resourceCloudStackInstanceRead(d *schema.ResourceData, meta interface{}) er
 		d.Set("security_group_names", groups)
 	}
 
+	// The host is only known while the instance is running and is only
+	// returned for root admins. A stopped instance has no placement.
+	if vm.Hostid != "" {
+		host, _, err := cs.Host.GetHostByID(vm.Hostid)
+		if err != nil {
+			return err
+		}
+
+		d.Set("host_id", host.Id)
+		d.Set("cluster_id", host.Clusterid)
+		d.Set("pod_id", host.Podid)
+	} else {
+		d.Set("host_id", "")
+		d.Set("cluster_id", "")
+		d.Set("pod_id", "")
+	}
+
+	// Only set the details managed by this resource, as CloudStack adds a
+	// number of details of its own
+	details := make(map[string]interface{})
+	for k := range d.Get("details").(map[string]interface{}) {
+		if v, ok := vm.Details[k]; ok {
+			details[k] = v
+		}
+	}
+	d.Set("details", details)
+
+	readInstanceExtraConfig(d, vm)
+
 	d.Set("tags", tagsToMap(vm.Tags))
 
 	setValueOrID(d, "service_offering", vm.Serviceofferingname, vm.Serviceofferingid)
 	setValueOrID(d, "template", vm.Templatename, vm.Templateid)
+
+	// An ISO is usually detached after installing from it, so only update it
+	// while it is still attached. ISOs attached to instances that are not
+	// deployed from an ISO are ignored.
+	if vm.Isoid != "" && d.Get("iso").(string) != "" {
+		setValueOrID(d, "iso", vm.Isoname, vm.Isoid)
+	}
+
+	d.Set("hypervisor", vm.Hypervisor)
 	setValueOrID(d, "project", vm.Project, vm.Projectid)
 	setValueOrID(d, "zone", vm.Zonename, vm.Zoneid)
 
@@ -570,9 +716,19 @@ func resourceCloudStackInstanceUpdate(d *schema.ResourceData, meta interface{})
 
 	}
 
+	// Without a PGP key the password is no longer stored
+	if d.HasChange("pgp_key") && d.Get("pgp_key").(string) == "" {
+		d.Set("pgp_key_fingerprint", "")
+		d.Set("encrypted_password", "")
+	}
+
 	// Attributes that require reboot to update
 	if d.HasChange("name") || d.HasChange("service_offering") || d.HasChange("affinity_group_ids") ||
-		d.HasChange("affinity_group_names") || d.HasChange("keypair") || d.HasChange("keypairs") || d.HasChange("user_data") {
+		d.HasChange("affinity_group_names") || d.HasChange("security_group_ids") || d.HasChange("security_group_names") ||
+		d.HasChange("keypair") || d.HasChange("keypairs") || d.HasChange("user_data") ||
+		d.HasChange("user_data_id") || d.HasChange("user_data_details") ||
+		d.HasChange("password_reset_trigger") || passwordRequiresReencryption(d) || d.HasChange("extra_config") ||
+		instanceDetailsRequireRestart(d) {
 
 		// Before we can actually make these changes, the virtual machine must be stopped
 		_, err := cs.VirtualMachine.StopVirtualMachine(
@@ -666,6 +822,54 @@ func resourceCloudStackInstanceUpdate(d *schema.ResourceData, meta interface{})
 			}
 		}
 
+		// Check if the security group IDs have changed and if so, update the IDs
+		if d.HasChange("security_group_ids") {
+			log.Printf("[DEBUG] Security groups changed for %s, starting update", name)
+
+			p := cs.VirtualMachine.NewUpdateVirtualMachineParams(d.Id())
+			groups := []string{}
+
+			if sgIDs := d.Get("security_group_ids").(*schema.Set); sgIDs.Len() > 0 {
+				for _, group := range sgIDs.List() {
+					groups = append(groups, group.(string))
+				}
+			}
+
+			// Set the new groups
+			p.SetSecuritygroupids(groups)
+
+			// Update the security groups
+			_, err = cs.VirtualMachine.UpdateVirtualMachine(p)
+			if err != nil {
+				return fmt.Errorf(
+					"Error updating the security groups for instance %s: %s", name, err)
+			}
+		}
+
+		// Check if the security group names have changed and if so, update the names
+		if d.HasChange("security_group_names") {
+			log.Printf("[DEBUG] Security groups changed for %s, starting update", name)
+
+			p := cs.VirtualMachine.NewUpdateVirtualMachineParams(d.Id())
+			groups := []string{}
+
+			if sgNames := d.Get("security_group_names").(*schema.Set); sgNames.Len() > 0 {
+				for _, group := range sgNames.List() {
+					groups = append(groups, group.(string))
+				}
+			}
+
+			// Set the new groups
+			p.SetSecuritygroupnames(groups)
+
+			// Update the security groups
+			_, err = cs.VirtualMachine.UpdateVirtualMachine(p)
+			if err != nil {
+				return fmt.Errorf(
+					"Error updating the security groups for instance %s: %s", name, err)
+			}
+		}
+
 		// Check if the keypair has changed and if so, update the keypair
 		if d.HasChange("keypair") || d.HasChange("keypairs") {
 			log.Printf("[DEBUG] SSH keypair(s) changed for %s, starting update", name)
@@ -726,15 +930,138 @@ func resourceCloudStackInstanceUpdate(d *schema.ResourceData, meta interface{})
 			}
 		}
 
+		// Check if the registered user data has changed and if so, update it
+		if d.HasChange("user_data_id") || d.HasChange("user_data_details") {
+			log.Printf("[DEBUG] user_data_id changed for %s, starting update", name)
+
+			if userdataid, ok := d.GetOk("user_data_id"); ok {
+				p := cs.VirtualMachine.NewUpdateVirtualMachineParams(d.Id())
+				p.SetUserdataid(userdataid.(string))
+
+				if userdatadetails, ok := d.GetOk("user_data_details"); ok {
+					p.SetUserdatadetails(stringMapFromSchema(userdatadetails.(map[string]interface{})))
+				}
+
+				_, err = cs.VirtualMachine.UpdateVirtualMachine(p)
+				if err != nil {
+					return fmt.Errorf(
+						"Error updating user_data_id for instance %s: %s", name, err)
+				}
+			} else if d.HasChange("user_data_id") && d.Get("user_data").(string) == "" {
+				// Detach the registered user data that is removed from the config
+				p := cs.VirtualMachine.NewResetUserDataForVirtualMachineParams(d.Id())
+
+				_, err = cs.VirtualMachine.ResetUserDataForVirtualMachine(p)
+				if err != nil {
+					return fmt.Errorf(
+						"Error removing user_data_id from instance %s: %s", name, err)
+				}
+			}
+		}
+
+		// Check if the password reset trigger or the PGP key has changed and if
+		// so, reset the password. The current password is not known, so a new
+		// password is the only way to encrypt it with a new PGP key.
+		if d.HasChange("password_reset_trigger") || passwordRequiresReencryption(d) {
+			log.Printf("[DEBUG] Password reset triggered for %s, starting reset", name)
+
+			r, err := cs.VirtualMachine.ResetPasswordForVirtualMachine(
+				cs.VirtualMachine.NewResetPasswordForVirtualMachineParams(d.Id()))
+			if err != nil {
+				return fmt.Errorf(
+					"Error resetting the password for instance %s: %s", name, err)
+			}
+
+			if err := setEncryptedPassword(d, r.Password); err != nil {
+				return fmt.Errorf(
+					"Error encrypting the password for instance %s: %s", name, err)
+			}
+		}
+
+		// Check if details that are only applied on start have changed and if so,
+		// update the details
+		if instanceDetailsRequireRestart(d) {
+			log.Printf("[DEBUG] Details changed for %s, starting update", name)
+
+			if err := updateInstanceDetails(d, meta); err != nil {
+				return fmt.Errorf(
+					"Error updating the details for instance %s: %s", name, err)
+			}
+		}
+
+		// Check if the extra configuration has changed and if so, update the
+		// extra configuration
+		if d.HasChange("extra_config") {
+			log.Printf("[DEBUG] Extra configuration changed for %s, starting update", name)
+
+			if err := updateInstanceExtraConfig(d, meta); err != nil {
+				return fmt.Errorf(
+					"Error updating the extra configuration for instance %s: %s", name, err)
+			}
+		}
+
 		// Start the virtual machine again
-		_, err = cs.VirtualMachine.StartVirtualMachine(
-			cs.VirtualMachine.NewStartVirtualMachineParams(d.Id()))
+		ps := cs.VirtualMachine.NewStartVirtualMachineParams(d.Id())
+		if d.Get("boot_into_setup").(bool) {
+			ps.SetBootintosetup(true)
+		}
+
+		_, err = cs.VirtualMachine.StartVirtualMachine(ps)
 		if err != nil {
 			return fmt.Errorf(
 				"Error starting instance %s after making changes", name)
 		}
 	}
 
+	// Check if the placement has changed and if so, migrate the instance
+	if d.HasChange("host_id") || d.HasChange("cluster_id") || d.HasChange("pod_id") {
+		if err := resourceCloudStackInstanceMigrate(d, meta); err != nil {
+			return fmt.Errorf("Error migrating instance %s: %s", name, err)
+		}
+	}
+
+	// Check if the root disk size has changed and if so, resize the root disk
+	if d.HasChange("root_disk_size") {
+		log.Printf("[DEBUG] Root disk size changed for %s, starting resize", name)
+
+		rootVolume, err := getRootVolume(cs, d.Id())
+		if err != nil {
+			return err
+		}
+		if rootVolume == nil {
+			return fmt.Errorf("Error resizing the root disk for instance %s: root disk not found", name)
+		}
+
+		_, err = resizeVolume(cs, rootVolume.Id, "",
+			int64(d.Get("root_disk_size").(int)), d.Get("root_disk_shrink_ok").(bool))
+		if err != nil {
+			return fmt.Errorf(
+				"Error resizing the root disk for instance %s: %s", name, err)
+		}
+	}
+
+	// Check if the root disk IOPS have changed and if so, update the root disk
+	if d.HasChange("root_disk_min_iops") || d.HasChange("root_disk_max_iops") {
+		log.Printf("[DEBUG] Root disk IOPS changed for %s, starting update", name)
+
+		rootVolume, err := getRootVolume(cs, d.Id())
+		if err != nil {
+			return err
+		}
+		if rootVolume == nil {
+			return fmt.Errorf("Error updating the root disk IOPS for instance %s: root disk not found", name)
+		}
+
+		p := cs.Volume.NewResizeVolumeParams(rootVolume.Id)
+		p.SetMiniops(int64(d.Get("root_disk_min_iops").(int)))
+		p.SetMaxiops(int64(d.Get("root_disk_max_iops").(int)))
+
+		if _, err := cs.Volume.ResizeVolume(p); err != nil {
+			return fmt.Errorf(
+				"Error updating the root disk IOPS for instance %s: %s", name, err)
+		}
+	}
+
 	// Check if the tags have changed and if so, update the tags
 	if d.HasChange("tags") {
 		if err := updateTags(cs, d, "UserVm"); err != nil {
@@ -743,15 +1070,13 @@ func resourceCloudStackInstanceUpdate(d *schema.ResourceData, meta interface{})
 	}
 
 	// Check if the details have changed and if so, update the details
-	if d.HasChange("details") {
-		p := cs.VirtualMachine.NewUpdateVirtualMachineParams(d.Id())
-		vmDetails := make(map[string]string)
-		if details := d.Get("details"); details != nil {
-			for k, v := range details.(map[string]interface{}) {
-				vmDetails[k] = v.(string)
-			}
+	if d.HasChange("details") && !instanceDetailsRequireRestart(d) {
+		log.Printf("[DEBUG] Details changed for %s, starting update", name)
+
+		if err := updateInstanceDetails(d, meta); err != nil {
+			return fmt.Errorf(
+				"Error updating the details for instance %s: %s", name, err)
 		}
-		p.SetDetails(vmDetails)
 	}
 
 	return resourceCloudStackInstanceRead(d, meta)
@@ -781,6 +1106,112 @@ func resourceCloudStackInstanceDelete(d *schema.ResourceData, meta interface{})
 
 	return nil
 }
+
+// suppressUnplacedInstanceDiff suppresses placement diffs of existing instances
+// that are not placed on a host, like stopped instances. The placement is
+// compared again once the instance is running.
+func suppressUnplacedInstanceDiff(k, old, new string, d *schema.ResourceData) bool {
+	return d.Id() != "" && old == ""
+}
+
+// resourceCloudStackInstanceMigrate live migrates the instance to the configured
+// host, or to a suitable host within the configured cluster or pod
+func resourceCloudStackInstanceMigrate(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Only filter on the attributes that were actually changed, as the others
+	// still contain the computed placement of the current host
+	filters := make(map[string]string)
+	for _, k := range []string{"host_id", "cluster_id", "pod_id"} {
+		if v := d.Get(k).(string); v != "" && d.HasChange(k) {
+			filters[k] = v
+		}
+	}
+
+	// Retrieve the hosts the instance can be migrated to
+	l, err := cs.Host.FindHostsForMigration(cs.Host.NewFindHostsForMigrationParams(d.Id()))
+	if err != nil {
+		return err
+	}
+
+	var host *cloudstack.HostForMigration
+	for _, h := range l.Host {
+		if !h.Suitableformigration {
+			continue
+		}
+		if v, ok := filters["host_id"]; ok && h.Id != v {
+			continue
+		}
+		if v, ok := filters["cluster_id"]; ok && h.Clusterid != v {
+			continue
+		}
+		if v, ok := filters["pod_id"]; ok && h.Podid != v {
+			continue
+		}
+
+		host = h
+		break
+	}
+
+	if host == nil {
+		return fmt.Errorf("No suitable host found matching %v", filters)
+	}
+
+	log.Printf("[DEBUG] Migrating instance %s to host %s", d.Id(), host.Name)
+
+	// Migrating to a host in another cluster also requires the volumes to be
+	// migrated to storage accessible from that host
+	if host.RequiresStorageMotion {
+		p := cs.VirtualMachine.NewMigrateVirtualMachineWithVolumeParams(d.Id())
+		p.SetHostid(host.Id)
+
+		_, err = cs.VirtualMachine.MigrateVirtualMachineWithVolume(p)
+		return err
+	}
+
+	p := cs.VirtualMachine.NewMigrateVirtualMachineParams(d.Id())
+	p.SetHostid(host.Id)
+
+	_, err = cs.VirtualMachine.MigrateVirtualMachine(p)
+	return err
+}
+
+func resourceCloudStackInstanceCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	// Deploying from an ISO requires a hypervisor and a root disk offering,
+	// values that are not yet known are checked once they are known
+	if d.Id() == "" && d.Get("iso").(string) != "" {
+		if d.NewValueKnown("hypervisor") && d.Get("hypervisor").(string) == "" {
+			return fmt.Errorf("hypervisor must be set when deploying from an ISO")
+		}
+		if d.NewValueKnown("root_disk_offering") && d.Get("root_disk_offering").(string) == "" {
+			return fmt.Errorf("root_disk_offering must be set when deploying from an ISO")
+		}
+	}
+
+	// Resetting the password or changing the PGP key changes the stored password
+	if d.Id() != "" && (d.HasChange("password_reset_trigger") || d.HasChange("pgp_key")) {
+		if err := d.SetNewComputed("encrypted_password"); err != nil {
+			return err
+		}
+		if err := d.SetNewComputed("pgp_key_fingerprint"); err != nil {
+			return err
+		}
+	}
+
+	// The root disk is resized in place, unless it needs to shrink while
+	// shrinking is not allowed. In that case the instance is replaced.
+	if d.Id() != "" && d.HasChange("root_disk_size") {
+		o, n := d.GetChange("root_disk_size")
+		if n.(int) < o.(int) && !d.Get("root_disk_shrink_ok").(bool) {
+			if err := d.ForceNew("root_disk_size"); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
 func resourceCloudStackInstanceImportContext(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	// We set start_vm to true as that matches the default and we assume that
 	// when you need to import an instance it means it is already running.
@@ -788,6 +1219,738 @@ func resourceCloudStackInstanceImportContext(ctx context.Context, d *schema.Reso
 	return importStatePassthroughContext(ctx, d, meta)
 }
 
+// kvmExtraConfigName matches the line naming a block of KVM extra configuration
+var kvmExtraConfigName = regexp.MustCompile(`^\S+:$`)
+
+// instanceDetailsRestart contains the details that are only applied when the
+// instance is (re)started
+var instanceDetailsRestart = map[string]bool{
+	"dataDiskController": true,
+	"keyboard":           true,
+	"nicAdapter":         true,
+	"rootDiskController": true,
+	"svga.vramSize":      true,
+	"video.hardware":     true,
+	"video.ram":          true,
+}
+
+// instanceDetailsRequireRestart returns true if any of the changed details
+// requires the instance to be restarted
+func instanceDetailsRequireRestart(d *schema.ResourceData) bool {
+	if !d.HasChange("details") {
+		return false
+	}
+
+	o, n := d.GetChange("details")
+	om := o.(map[string]interface{})
+	nm := n.(map[string]interface{})
+
+	for k := range instanceDetailsRestart {
+		if om[k] != nm[k] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// updateInstanceDetails updates the details of the instance. As CloudStack
+// replaces all existing details, the details not managed by this resource are
+// merged with the configured details. When no details remain at all, the
+// existing details are cleaned up instead.
+func updateInstanceDetails(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	vm, _, err := cs.VirtualMachine.GetVirtualMachineByID(
+		d.Id(),
+		cloudstack.WithProject(d.Get("project").(string)),
+	)
+	if err != nil {
+		return err
+	}
+
+	o, n := d.GetChange("details")
+	managed := n.(map[string]interface{})
+
+	// Read-only details cannot be passed by regular users and are retained
+	// by CloudStack anyway
+	readonly := make(map[string]bool)
+	for _, k := range strings.Split(vm.Readonlydetails, ",") {
+		readonly[strings.TrimSpace(k)] = true
+	}
+
+	vmDetails := make(map[string]string)
+	for k, v := range vm.Details {
+		// Skip the previously managed details, so removed details are dropped
+		if _, ok := o.(map[string]interface{})[k]; ok || readonly[k] {
+			continue
+		}
+		vmDetails[k] = v
+	}
+	for k, v := range managed {
+		vmDetails[k] = v.(string)
+	}
+
+	// Create a new parameter struct
+	p := cs.VirtualMachine.NewUpdateVirtualMachineParams(d.Id())
+
+	if len(vmDetails) > 0 {
+		p.SetDetails(vmDetails)
+	} else {
+		p.SetCleanupdetails(true)
+	}
+
+	_, err = cs.VirtualMachine.UpdateVirtualMachine(p)
+	return err
+}
+
+// isExtraConfigDetail returns true if the detail contains extra configuration
+func isExtraConfigDetail(key string) bool {
+	return strings.HasPrefix(key, "extraconfig-")
+}
+
+// updateInstanceExtraConfig replaces the extra configuration of the instance.
+// Extra configuration is only ever added by CloudStack, so the existing extra
+// configuration is removed first.
+func updateInstanceExtraConfig(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	vm, _, err := cs.VirtualMachine.GetVirtualMachineByID(
+		d.Id(),
+		cloudstack.WithProject(d.Get("project").(string)),
+	)
+	if err != nil {
+		return err
+	}
+
+	readonly := make(map[string]bool)
+	for _, k := range strings.Split(vm.Readonlydetails, ",") {
+		readonly[strings.TrimSpace(k)] = true
+	}
+
+	hasExtraConfig := false
+	vmDetails := make(map[string]string)
+	for k, v := range vm.Details {
+		if isExtraConfigDetail(k) {
+			hasExtraConfig = true
+			continue
+		}
+		if !readonly[k] {
+			vmDetails[k] = v
+		}
+	}
+
+	if hasExtraConfig {
+		p := cs.VirtualMachine.NewUpdateVirtualMachineParams(d.Id())
+
+		if len(vmDetails) > 0 {
+			p.SetDetails(vmDetails)
+		} else {
+			p.SetCleanupdetails(true)
+		}
+
+		if _, err := cs.VirtualMachine.UpdateVirtualMachine(p); err != nil {
+			return err
+		}
+	}
+
+	if extraconfig, ok := d.GetOk("extra_config"); ok {
+		p := cs.VirtualMachine.NewUpdateVirtualMachineParams(d.Id())
+		p.SetExtraconfig(url.QueryEscape(extraconfig.(string)))
+
+		if _, err := cs.VirtualMachine.UpdateVirtualMachine(p); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readInstanceExtraConfig updates the extra configuration from the details
+// of the instance. CloudStack stores each (named) block of a KVM extra
+// configuration as a separate detail, which allows detecting changes. For
+// other hypervisors only the removal of the extra configuration is detected.
+func readInstanceExtraConfig(d *schema.ResourceData, vm *cloudstack.VirtualMachine) {
+	actual := make(map[string]string)
+	for k, v := range vm.Details {
+		if isExtraConfigDetail(k) {
+			actual[k] = v
+		}
+	}
+
+	if len(actual) == 0 {
+		d.Set("extra_config", "")
+		return
+	}
+
+	if !strings.EqualFold(vm.Hypervisor, "KVM") {
+		return
+	}
+
+	if reflect.DeepEqual(actual, kvmExtraConfigDetails(d.Get("extra_config").(string))) {
+		return
+	}
+
+	keys := make([]string, 0, len(actual))
+	for k := range actual {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var blocks []string
+	for _, k := range keys {
+		blocks = append(blocks, strings.TrimPrefix(k, "extraconfig-")+":\n"+actual[k])
+	}
+	d.Set("extra_config", strings.Join(blocks, "\n\n"))
+}
+
+// kvmExtraConfigDetails returns the details CloudStack stores for a KVM extra
+// configuration. Blocks are separated by an empty line and can be named by
+// starting them with a "name:" line.
+func kvmExtraConfigDetails(extraconfig string) map[string]string {
+	details := make(map[string]string)
+	if extraconfig == "" {
+		return details
+	}
+
+	for i, block := range strings.Split(extraconfig, "\n\n") {
+		lines := strings.SplitN(block, "\n", 2)
+		if len(lines) == 2 && kvmExtraConfigName.MatchString(lines[0]) {
+			details["extraconfig-"+strings.TrimSuffix(lines[0], ":")] = lines[1]
+		} else {
+			details["extraconfig-"+strconv.Itoa(i+1)] = block
+		}
+	}
+
+	return details
+}
+
+// instanceDeployParams contains the deploy parameters that are shared by the
+// deploy parameter struct of the client and the custom deploy request used to
+// deploy from a volume or snapshot
+type instanceDeployParams interface {
+	cloudstack.ProjectIDSetter
+	SetAffinitygroupids([]string)
+	SetAffinitygroupnames([]string)
+	SetBootintosetup(bool)
+	SetBootmode(string)
+	SetBoottype(string)
+	SetClusterid(string)
+	SetDetails(map[string]string)
+	SetDisplayname(string)
+	SetExtraconfig(string)
+	SetGroup(string)
+	SetHostid(string)
+	SetHypervisor(string)
+	SetIp6address(string)
+	SetIpaddress(string)
+	SetKeypair(string)
+	SetKeypairs([]string)
+	SetName(string)
+	SetNetworkids([]string)
+	SetNicnetworklist([]map[string]string)
+	SetPodid(string)
+	SetProperties(map[string]string)
+	SetRootdisksize(int64)
+	SetSecuritygroupids([]string)
+	SetSecuritygroupnames([]string)
+	SetStartvm(bool)
+	SetUserdata(string)
+	SetUserdatadetails(map[string]string)
+	SetUserdataid(string)
+}
+
+// setInstanceDeployParams sets the deploy parameters that do not depend on
+// what the instance is deployed from
+func setInstanceDeployParams(
+	cs *cloudstack.CloudStackClient,
+	d *schema.ResourceData,
+	p instanceDeployParams,
+	zone *cloudstack.Zone) error {
+	p.SetStartvm(d.Get("start_vm").(bool))
+
+	vmDetails := make(map[string]string)
+	if details, ok := d.GetOk("details"); ok {
+		for k, v := range details.(map[string]interface{}) {
+			vmDetails[k] = v.(string)
+		}
+	}
+
+	// The IOPS of a root disk offering with custom IOPS are passed as details
+	if miniops, ok := d.GetOk("root_disk_min_iops"); ok {
+		vmDetails["minIopsDo"] = strconv.Itoa(miniops.(int))
+	}
+	if maxiops, ok := d.GetOk("root_disk_max_iops"); ok {
+		vmDetails["maxIopsDo"] = strconv.Itoa(maxiops.(int))
+	}
+
+	if len(vmDetails) > 0 {
+		p.SetDetails(vmDetails)
+	}
+
+	// CloudStack expects the extra configuration to be URL encoded
+	if extraconfig, ok := d.GetOk("extra_config"); ok {
+		p.SetExtraconfig(url.QueryEscape(extraconfig.(string)))
+	}
+
+	// Set VM Properties
+	if properties, ok := d.GetOk("properties"); ok {
+		p.SetProperties(stringMapFromSchema(properties.(map[string]interface{})))
+	}
+
+	// SetNicNetworkList
+	if nicnetworklist, ok := d.GetOk("nicnetworklist"); ok {
+		nicNetworkDetails := []map[string]string{
+			{
+				"nic":     nicnetworklist.(map[string]interface{})["nic"].(string),
+				"network": nicnetworklist.(map[string]interface{})["network"].(string),
+			},
+		}
+		p.SetNicnetworklist(nicNetworkDetails)
+	}
+
+	// Set the name
+	name, hasName := d.GetOk("name")
+	if hasName {
+		p.SetName(name.(string))
+	}
+
+	// Set the display name
+	if displayname, ok := d.GetOk("display_name"); ok {
+		p.SetDisplayname(displayname.(string))
+	} else if hasName {
+		p.SetDisplayname(name.(string))
+	}
+
+	// If there is a root_disk_size supplied, add it to the parameter struct
+	if rootdisksize, ok := d.GetOk("root_disk_size"); ok {
+		p.SetRootdisksize(int64(rootdisksize.(int)))
+	}
+
+	// Set the boot type and mode, where the deprecated uefi flag means UEFI
+	// in legacy mode
+	if d.Get("uefi").(bool) {
+		p.SetBoottype("UEFI")
+		p.SetBootmode("Legacy")
+	} else if boottype, ok := d.GetOk("boot_type"); ok {
+		p.SetBoottype(strings.ToUpper(boottype.(string)))
+
+		if bootmode, ok := d.GetOk("boot_mode"); ok {
+			p.SetBootmode(bootmode.(string))
+		} else if strings.EqualFold(boottype.(string), "UEFI") {
+			p.SetBootmode("Legacy")
+		}
+	}
+
+	if d.Get("boot_into_setup").(bool) {
+		p.SetBootintosetup(true)
+	}
+
+	// If there is a hypervisor supplied, add it to the parameter struct
+	if hypervisor, ok := d.GetOk("hypervisor"); ok {
+		p.SetHypervisor(hypervisor.(string))
+	}
+
+	if zone.Networktype == "Advanced" {
+		// Set the default network ID
+		p.SetNetworkids([]string{d.Get("network_id").(string)})
+	}
+
+	// If there is a ipaddres supplied, add it to the parameter struct
+	if ipaddress, ok := d.GetOk("ip_address"); ok {
+		p.SetIpaddress(ipaddress.(string))
+	}
+
+	// If there is a ip6address supplied, add it to the parameter struct
+	if ip6address, ok := d.GetOk("ip6_address"); ok {
+		p.SetIp6address(ip6address.(string))
+	}
+
+	// If there is a group supplied, add it to the parameter struct
+	if group, ok := d.GetOk("group"); ok {
+		p.SetGroup(group.(string))
+	}
+
+	// If there are affinity group IDs supplied, add them to the parameter struct
+	if agIDs := d.Get("affinity_group_ids").(*schema.Set); agIDs.Len() > 0 {
+		var groups []string
+		for _, group := range agIDs.List() {
+			groups = append(groups, group.(string))
+		}
+		p.SetAffinitygroupids(groups)
+	}
+
+	// If there are affinity group names supplied, add them to the parameter struct
+	if agNames := d.Get("affinity_group_names").(*schema.Set); agNames.Len() > 0 {
+		var groups []string
+		for _, group := range agNames.List() {
+			groups = append(groups, group.(string))
+		}
+		p.SetAffinitygroupnames(groups)
+	}
+
+	// If there are security group IDs supplied, add them to the parameter struct
+	if sgIDs := d.Get("security_group_ids").(*schema.Set); sgIDs.Len() > 0 {
+		var groups []string
+		for _, group := range sgIDs.List() {
+			groups = append(groups, group.(string))
+		}
+		p.SetSecuritygroupids(groups)
+	}
+
+	// If there are security group names supplied, add them to the parameter struct
+	if sgNames := d.Get("security_group_names").(*schema.Set); sgNames.Len() > 0 {
+		var groups []string
+		for _, group := range sgNames.List() {
+			groups = append(groups, group.(string))
+		}
+		p.SetSecuritygroupnames(groups)
+	}
+
+	// If there is a project supplied, we retrieve and set the project id
+	if err := setProjectid(p, cs, d); err != nil {
+		return err
+	}
+
+	// If a keypair is supplied, add it to the parameter struct
+	if keypair, ok := d.GetOk("keypair"); ok {
+		p.SetKeypair(keypair.(string))
+	}
+
+	if keypairs, ok := d.GetOk("keypairs"); ok {
+		var keypairStrings []string
+		for _, kp := range keypairs.([]interface{}) {
+			keypairStrings = append(keypairStrings, fmt.Sprintf("%v", kp))
+		}
+		p.SetKeypairs(keypairStrings)
+	}
+
+	// If a host_id is supplied, add it to the parameter struct
+	if hostid, ok := d.GetOk("host_id"); ok {
+		p.SetHostid(hostid.(string))
+	}
+
+	// If a pod_id is supplied, add it to the parameter struct
+	if podid, ok := d.GetOk("pod_id"); ok {
+		p.SetPodid(podid.(string))
+	}
+
+	// If a cluster_id is supplied, add it to the parameter struct
+	if clusterid, ok := d.GetOk("cluster_id"); ok {
+		p.SetClusterid(clusterid.(string))
+	}
+
+	if userData, ok := d.GetOk("user_data"); ok {
+		ud, err := getUserData(userData.(string))
+		if err != nil {
+			return err
+		}
+		p.SetUserdata(ud)
+	}
+
+	// If registered user data is supplied, add it to the parameter struct
+	if userdataid, ok := d.GetOk("user_data_id"); ok {
+		p.SetUserdataid(userdataid.(string))
+	}
+
+	if userdatadetails, ok := d.GetOk("user_data_details"); ok {
+		p.SetUserdatadetails(stringMapFromSchema(userdatadetails.(map[string]interface{})))
+	}
+
+	return nil
+}
+
+// customDeployParams implements the shared deploy parameters for a custom
+// deployVirtualMachine request, encoding them the way the client does
+type customDeployParams struct {
+	*cloudstack.CustomServiceParams
+}
+
+func (p *customDeployParams) SetAffinitygroupids(v []string) {
+	p.SetParam("affinitygroupids", strings.Join(v, ","))
+}
+
+func (p *customDeployParams) SetAffinitygroupnames(v []string) {
+	p.SetParam("affinitygroupnames", strings.Join(v, ","))
+}
+
+func (p *customDeployParams) SetBootintosetup(v bool) {
+	p.SetParam("bootintosetup", v)
+}
+
+func (p *customDeployParams) SetBootmode(v string) {
+	p.SetParam("bootmode", v)
+}
+
+func (p *customDeployParams) SetBoottype(v string) {
+	p.SetParam("boottype", v)
+}
+
+func (p *customDeployParams) SetClusterid(v string) {
+	p.SetParam("clusterid", v)
+}
+
+func (p *customDeployParams) SetDetails(v map[string]string) {
+	keys := make([]string, 0, len(v))
+	for k := range v {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for i, k := range keys {
+		p.SetParam(fmt.Sprintf("details[%d].%s", i, k), v[k])
+	}
+}
+
+func (p *customDeployParams) SetDisplayname(v string) {
+	p.SetParam("displayname", v)
+}
+
+func (p *customDeployParams) SetExtraconfig(v string) {
+	p.SetParam("extraconfig", v)
+}
+
+func (p *customDeployParams) SetGroup(v string) {
+	p.SetParam("group", v)
+}
+
+func (p *customDeployParams) SetHostid(v string) {
+	p.SetParam("hostid", v)
+}
+
+func (p *customDeployParams) SetHypervisor(v string) {
+	p.SetParam("hypervisor", v)
+}
+
+func (p *customDeployParams) SetIp6address(v string) {
+	p.SetParam("ip6address", v)
+}
+
+func (p *customDeployParams) SetIpaddress(v string) {
+	p.SetParam("ipaddress", v)
+}
+
+func (p *customDeployParams) SetKeypair(v string) {
+	p.SetParam("keypair", v)
+}
+
+func (p *customDeployParams) SetKeypairs(v []string) {
+	p.SetParam("keypairs", strings.Join(v, ","))
+}
+
+func (p *customDeployParams) SetName(v string) {
+	p.SetParam("name", v)
+}
+
+func (p *customDeployParams) SetNetworkids(v []string) {
+	p.SetParam("networkids", strings.Join(v, ","))
+}
+
+func (p *customDeployParams) SetNicnetworklist(v []map[string]string) {
+	for i, m := range v {
+		for key, val := range m {
+			p.SetParam(fmt.Sprintf("nicnetworklist[%d].%s", i, key), val)
+		}
+	}
+}
+
+func (p *customDeployParams) SetPodid(v string) {
+	p.SetParam("podid", v)
+}
+
+func (p *customDeployParams) SetProjectid(v string) {
+	p.SetParam("projectid", v)
+}
+
+func (p *customDeployParams) SetProperties(v map[string]string) {
+	setKeyValueParams(p.CustomServiceParams, "properties", v)
+}
+
+func (p *customDeployParams) SetRootdisksize(v int64) {
+	p.SetParam("rootdisksize", v)
+}
+
+func (p *customDeployParams) SetSecuritygroupids(v []string) {
+	p.SetParam("securitygroupids", strings.Join(v, ","))
+}
+
+func (p *customDeployParams) SetSecuritygroupnames(v []string) {
+	p.SetParam("securitygroupnames", strings.Join(v, ","))
+}
+
+func (p *customDeployParams) SetStartvm(v bool) {
+	p.SetParam("startvm", v)
+}
+
+func (p *customDeployParams) SetUserdata(v string) {
+	p.SetParam("userdata", v)
+}
+
+func (p *customDeployParams) SetUserdataid(v string) {
+	p.SetParam("userdataid", v)
+}
+
+func (p *customDeployParams) SetUserdatadetails(v map[string]string) {
+	setKeyValueParams(p.CustomServiceParams, "userdatadetails", v)
+}
+
+// deployVirtualMachineFromVolume deploys a new instance from an existing volume
+// or a volume snapshot, using a custom deployVirtualMachine request
+func deployVirtualMachineFromVolume(
+	d *schema.ResourceData,
+	meta interface{},
+	serviceofferingid string,
+	zone *cloudstack.Zone) (*cloudstack.DeployVirtualMachineResponse, error) {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Create a new parameter struct
+	p := &customDeployParams{&cloudstack.CustomServiceParams{}}
+	p.SetParam("serviceofferingid", serviceofferingid)
+	p.SetParam("zoneid", zone.Id)
+
+	if volumeid, ok := d.GetOk("volume_id"); ok {
+		p.SetParam("volumeid", volumeid.(string))
+	}
+
+	if snapshotid, ok := d.GetOk("snapshot_id"); ok {
+		p.SetParam("snapshotid", snapshotid.(string))
+	}
+
+	// Set the parameters shared with deploying from a template or ISO
+	if err := setInstanceDeployParams(cs, d, p, zone); err != nil {
+		return nil, err
+	}
+
+	// Deploy the instance and wait for the deployment to finish
+	var result struct {
+		VirtualMachine *cloudstack.DeployVirtualMachineResponse `json:"virtualmachine"`
+	}
+	err := customAsyncRequest(cs, "deployVirtualMachine", p.CustomServiceParams, &result, d.Timeout(schema.TimeoutCreate))
+	if err != nil {
+		return nil, err
+	}
+	if result.VirtualMachine == nil {
+		return nil, fmt.Errorf("Unexpected deploy result without virtual machine")
+	}
+
+	return result.VirtualMachine, nil
+}
+
+// setKeyValueParams sets a map parameter of a custom request in the key/value
+// format CloudStack expects for properties and user data details
+func setKeyValueParams(p *cloudstack.CustomServiceParams, name string, m map[string]string) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for i, k := range keys {
+		p.SetParam(fmt.Sprintf("%s[%d].key", name, i), k)
+		p.SetParam(fmt.Sprintf("%s[%d].value", name, i), m[k])
+	}
+}
+
+// passwordRequiresReencryption returns true if the PGP key of an instance with
+// a stored password has changed to another PGP key
+func passwordRequiresReencryption(d *schema.ResourceData) bool {
+	return d.HasChange("pgp_key") && d.Get("pgp_key").(string) != "" &&
+		d.Get("encrypted_password").(string) != ""
+}
+
+// setEncryptedPassword stores the password of the instance encrypted with the
+// configured PGP key. Without a PGP key the password is not stored.
+func setEncryptedPassword(d *schema.ResourceData, password string) error {
+	pgpKey, ok := d.GetOk("pgp_key")
+	if !ok || password == "" {
+		return nil
+	}
+
+	fingerprint, encrypted, err := encryptWithPGPKey(pgpKey.(string), password)
+	if err != nil {
+		return err
+	}
+
+	d.Set("pgp_key_fingerprint", fingerprint)
+	d.Set("encrypted_password", encrypted)
+
+	return nil
+}
+
+// getRootVolume returns the ROOT volume of the given instance, or nil if it
+// could not be found
+func getRootVolume(cs *cloudstack.CloudStackClient, virtualmachineid string) (*cloudstack.Volume, error) {
+	// Create a new param struct.
+	p := cs.Volume.NewListVolumesParams()
+	p.SetType("ROOT")
+	p.SetVirtualmachineid(virtualmachineid)
+
+	l, err := cs.Volume.ListVolumes(p)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(l.Volumes) != 1 {
+		return nil, nil
+	}
+
+	return l.Volumes[0], nil
+}
+
+// readInstanceDataDisk updates the data disk that was created together with
+// the instance
+func readInstanceDataDisk(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	if _, ok := d.GetOk("data_disk"); !ok {
+		return nil
+	}
+
+	var volume *cloudstack.Volume
+	if id := d.Get("data_disk.0.id").(string); id != "" {
+		v, count, err := cs.Volume.GetVolumeByID(id, cloudstack.WithProject(d.Get("project").(string)))
+		if err != nil {
+			if count == 0 {
+				// Keep the data disk, as it can only be recreated by replacing
+				// the whole instance including its root disk
+				log.Printf("[WARN] Data disk %s of instance %s does no longer exist", id, d.Id())
+				return nil
+			}
+			return err
+		}
+		volume = v
+	} else {
+		// Right after deploying the instance, its only data disk is the one
+		// that was created together with it
+		p := cs.Volume.NewListVolumesParams()
+		p.SetType("DATADISK")
+		p.SetVirtualmachineid(d.Id())
+
+		l, err := cs.Volume.ListVolumes(p)
+		if err != nil {
+			return err
+		}
+		if len(l.Volumes) != 1 {
+			log.Printf("[DEBUG] Failed to find data disk of instance: %s", d.Id())
+			return nil
+		}
+		volume = l.Volumes[0]
+	}
+
+	diskoffering := volume.Diskofferingname
+	if cloudstack.IsID(d.Get("data_disk.0.disk_offering").(string)) {
+		diskoffering = volume.Diskofferingid
+	}
+
+	return d.Set("data_disk", []interface{}{
+		map[string]interface{}{
+			"id":            volume.Id,
+			"disk_offering": diskoffering,
+			"size":          int(volume.Size >> 30), // B to GiB
+		},
+	})
+}
+
 // getUserData returns the user data as a base64 encoded string
 func getUserData(userData string) (string, error) {
 	ud := userData
@@ -0,0 +1,328 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// backupSchedule is a backup schedule as returned by listBackupSchedule,
+// which is not supported by the client
+type backupSchedule struct {
+	Id               string `json:"id"`
+	Virtualmachineid string `json:"virtualmachineid"`
+	Intervaltype     string `json:"intervaltype"`
+	Schedule         string `json:"schedule"`
+	Timezone         string `json:"timezone"`
+	Maxbackups       int    `json:"maxbackups"`
+}
+
+func resourceCloudStackInstanceBackupPolicy() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceCloudStackInstanceBackupPolicyCreate,
+		Read:   resourceCloudStackInstanceBackupPolicyRead,
+		Update: resourceCloudStackInstanceBackupPolicyUpdate,
+		Delete: resourceCloudStackInstanceBackupPolicyDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: importStatePassthroughContext,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"virtual_machine_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"backup_offering_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"backup_offering_name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"schedule": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"interval_type": {
+							Type:     schema.TypeString,
+							Required: true,
+							ValidateFunc: validation.StringInSlice([]string{
+								"HOURLY", "DAILY", "WEEKLY", "MONTHLY",
+							}, false),
+						},
+
+						"schedule": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+
+						"timezone": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+
+						"max_backups": {
+							Type:     schema.TypeInt,
+							Optional: true,
+						},
+					},
+				},
+			},
+
+			"project": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"forced_removal": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+		},
+	}
+}
+
+func resourceCloudStackInstanceBackupPolicyCreate(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	virtualmachineid := d.Get("virtual_machine_id").(string)
+
+	// Create a new parameter struct
+	p := &cloudstack.CustomServiceParams{}
+	p.SetParam("virtualmachineid", virtualmachineid)
+	p.SetParam("backupofferingid", d.Get("backup_offering_id").(string))
+
+	log.Printf("[DEBUG] Assigning virtual machine %s to backup offering %s",
+		virtualmachineid, d.Get("backup_offering_id").(string))
+	err := customAsyncRequest(cs, "assignVirtualMachineToBackupOffering", p, nil, d.Timeout(schema.TimeoutCreate))
+	if err != nil {
+		return fmt.Errorf(
+			"Error assigning virtual machine %s to backup offering: %s", virtualmachineid, err)
+	}
+
+	d.SetId(virtualmachineid)
+
+	for _, s := range d.Get("schedule").(*schema.Set).List() {
+		if err := createBackupSchedule(cs, virtualmachineid, s.(map[string]interface{})); err != nil {
+			return fmt.Errorf(
+				"Error creating backup schedule for virtual machine %s: %s", virtualmachineid, err)
+		}
+	}
+
+	return resourceCloudStackInstanceBackupPolicyRead(d, meta)
+}
+
+func resourceCloudStackInstanceBackupPolicyRead(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Get the virtual machine details
+	vm, count, err := cs.VirtualMachine.GetVirtualMachineByID(
+		d.Id(),
+		cloudstack.WithProject(d.Get("project").(string)),
+	)
+	if err != nil {
+		if count == 0 {
+			log.Printf("[DEBUG] Virtual machine %s does no longer exist", d.Id())
+			d.SetId("")
+			return nil
+		}
+
+		return err
+	}
+
+	if vm.Backupofferingid == "" {
+		log.Printf("[DEBUG] Virtual machine %s is no longer assigned to a backup offering", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("virtual_machine_id", vm.Id)
+	d.Set("backup_offering_id", vm.Backupofferingid)
+	d.Set("backup_offering_name", vm.Backupofferingname)
+
+	setValueOrID(d, "project", vm.Project, vm.Projectid)
+
+	schedules, err := listBackupSchedules(cs, d.Id())
+	if err != nil {
+		return err
+	}
+
+	var result []interface{}
+	for _, s := range schedules {
+		result = append(result, map[string]interface{}{
+			"interval_type": s.Intervaltype,
+			"schedule":      s.Schedule,
+			"timezone":      s.Timezone,
+			"max_backups":   s.Maxbackups,
+		})
+	}
+	d.Set("schedule", result)
+
+	return nil
+}
+
+func resourceCloudStackInstanceBackupPolicyUpdate(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Check if the schedules have changed and if so, update the schedules
+	if d.HasChange("schedule") {
+		o, n := d.GetChange("schedule")
+		ors := o.(*schema.Set).Difference(n.(*schema.Set))
+		nrs := n.(*schema.Set).Difference(o.(*schema.Set))
+
+		// Schedules are unique per interval type, so only the schedules of
+		// interval types that are no longer configured need to be deleted
+		intervals := make(map[string]bool)
+		for _, s := range nrs.List() {
+			intervals[s.(map[string]interface{})["interval_type"].(string)] = true
+		}
+
+		schedules, err := listBackupSchedules(cs, d.Id())
+		if err != nil {
+			return err
+		}
+
+		for _, s := range ors.List() {
+			intervaltype := s.(map[string]interface{})["interval_type"].(string)
+			if intervals[intervaltype] {
+				continue
+			}
+
+			for _, schedule := range schedules {
+				if schedule.Intervaltype != intervaltype {
+					continue
+				}
+
+				p := &cloudstack.CustomServiceParams{}
+				p.SetParam("id", schedule.Id)
+
+				var r map[string]interface{}
+				if err := customRequest(cs, "deleteBackupSchedule", p, &r); err != nil {
+					return fmt.Errorf(
+						"Error deleting %s backup schedule of virtual machine %s: %s", intervaltype, d.Id(), err)
+				}
+			}
+		}
+
+		// Creating a schedule for an existing interval type updates it
+		for _, s := range nrs.List() {
+			if err := createBackupSchedule(cs, d.Id(), s.(map[string]interface{})); err != nil {
+				return fmt.Errorf(
+					"Error updating backup schedule for virtual machine %s: %s", d.Id(), err)
+			}
+		}
+	}
+
+	return resourceCloudStackInstanceBackupPolicyRead(d, meta)
+}
+
+func resourceCloudStackInstanceBackupPolicyDelete(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Create a new parameter struct
+	p := &cloudstack.CustomServiceParams{}
+	p.SetParam("virtualmachineid", d.Id())
+	p.SetParam("forced", d.Get("forced_removal").(bool))
+
+	log.Printf("[INFO] Removing virtual machine %s from its backup offering", d.Id())
+	err := customAsyncRequest(cs, "removeVirtualMachineFromBackupOffering", p, nil, d.Timeout(schema.TimeoutDelete))
+	if err != nil {
+		// This is a very poor way to be told the ID does no longer exist :(
+		if strings.Contains(err.Error(), fmt.Sprintf(
+			"Invalid parameter virtualmachineid value=%s due to incorrect long value format, "+
+				"or entity does not exist", d.Id())) {
+			return nil
+		}
+
+		return fmt.Errorf(
+			"Error removing virtual machine %s from its backup offering: %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+// createBackupSchedule creates or updates the backup schedule of the given
+// interval type for a virtual machine
+func createBackupSchedule(cs *cloudstack.CloudStackClient, virtualmachineid string, schedule map[string]interface{}) error {
+	p := &cloudstack.CustomServiceParams{}
+	p.SetParam("virtualmachineid", virtualmachineid)
+	p.SetParam("intervaltype", schedule["interval_type"].(string))
+	p.SetParam("schedule", schedule["schedule"].(string))
+	p.SetParam("timezone", schedule["timezone"].(string))
+
+	if maxbackups := schedule["max_backups"].(int); maxbackups > 0 {
+		p.SetParam("maxbackups", maxbackups)
+	}
+
+	var r map[string]interface{}
+	return customRequest(cs, "createBackupSchedule", p, &r)
+}
+
+// listBackupSchedules returns the backup schedules of a virtual machine
+func listBackupSchedules(cs *cloudstack.CloudStackClient, virtualmachineid string) ([]*backupSchedule, error) {
+	p := &cloudstack.CustomServiceParams{}
+	p.SetParam("virtualmachineid", virtualmachineid)
+
+	var l struct {
+		BackupSchedule json.RawMessage `json:"backupschedule"`
+	}
+	if err := customRequest(cs, "listBackupSchedule", p, &l); err != nil {
+		return nil, err
+	}
+
+	if len(l.BackupSchedule) == 0 {
+		return nil, nil
+	}
+
+	// Older CloudStack versions return a single schedule instead of a list
+	var schedules []*backupSchedule
+	if err := json.Unmarshal(l.BackupSchedule, &schedules); err != nil {
+		var schedule backupSchedule
+		if err := json.Unmarshal(l.BackupSchedule, &schedule); err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, &schedule)
+	}
+
+	return schedules, nil
+}
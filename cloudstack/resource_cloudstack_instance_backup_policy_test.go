@@ -0,0 +1,209 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccCloudStackInstanceBackupPolicy_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackInstanceBackupPolicyDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackInstanceBackupPolicy_basic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackInstanceBackupPolicyExists(
+						"cloudstack_instance_backup_policy.foo"),
+					resource.TestCheckResourceAttrPair(
+						"cloudstack_instance_backup_policy.foo", "backup_offering_id",
+						"data.cloudstack_backup_offering.foo", "id"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_instance_backup_policy.foo", "schedule.#", "1"),
+				),
+			},
+
+			{
+				Config: testAccCloudStackInstanceBackupPolicy_update,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackInstanceBackupPolicyExists(
+						"cloudstack_instance_backup_policy.foo"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_instance_backup_policy.foo", "schedule.#", "2"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudStackInstanceBackupPolicy_import(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackInstanceBackupPolicyDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackInstanceBackupPolicy_basic,
+			},
+
+			{
+				ResourceName:            "cloudstack_instance_backup_policy.foo",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"forced_removal"},
+			},
+		},
+	})
+}
+
+func testAccCheckCloudStackInstanceBackupPolicyExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No backup policy ID is set")
+		}
+
+		cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+		vm, _, err := cs.VirtualMachine.GetVirtualMachineByID(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		if vm.Backupofferingid != rs.Primary.Attributes["backup_offering_id"] {
+			return fmt.Errorf("Virtual machine not assigned to backup offering")
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckCloudStackInstanceBackupPolicyDestroy(s *terraform.State) error {
+	cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "cloudstack_instance_backup_policy" {
+			continue
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No backup policy ID is set")
+		}
+
+		vm, _, err := cs.VirtualMachine.GetVirtualMachineByID(rs.Primary.ID)
+		if err == nil && vm.Backupofferingid != "" {
+			return fmt.Errorf(
+				"Virtual machine %s is still assigned to a backup offering", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+const testAccCloudStackInstanceBackupPolicy_basic = `
+resource "cloudstack_network" "foo" {
+  name = "terraform-network"
+  display_text = "terraform-network"
+  cidr = "10.1.1.0/24"
+  network_offering = "DefaultIsolatedNetworkOfferingWithSourceNatService"
+  zone = "Sandbox-simulator"
+}
+
+resource "cloudstack_instance" "foobar" {
+  name = "terraform-test"
+  display_name = "terraform-test"
+  service_offering= "Small Instance"
+  network_id = cloudstack_network.foo.id
+  template = "CentOS 5.6 (64-bit) no GUI (Simulator)"
+  zone = "Sandbox-simulator"
+  expunge = true
+}
+
+data "cloudstack_backup_offering" "foo" {
+  filter {
+    name = "name"
+    value = "Dummy Offering"
+  }
+}
+
+resource "cloudstack_instance_backup_policy" "foo" {
+  virtual_machine_id = cloudstack_instance.foobar.id
+  backup_offering_id = data.cloudstack_backup_offering.foo.id
+
+  schedule {
+    interval_type = "DAILY"
+    schedule = "00:02"
+    timezone = "UTC"
+  }
+}`
+
+const testAccCloudStackInstanceBackupPolicy_update = `
+resource "cloudstack_network" "foo" {
+  name = "terraform-network"
+  display_text = "terraform-network"
+  cidr = "10.1.1.0/24"
+  network_offering = "DefaultIsolatedNetworkOfferingWithSourceNatService"
+  zone = "Sandbox-simulator"
+}
+
+resource "cloudstack_instance" "foobar" {
+  name = "terraform-test"
+  display_name = "terraform-test"
+  service_offering= "Small Instance"
+  network_id = cloudstack_network.foo.id
+  template = "CentOS 5.6 (64-bit) no GUI (Simulator)"
+  zone = "Sandbox-simulator"
+  expunge = true
+}
+
+data "cloudstack_backup_offering" "foo" {
+  filter {
+    name = "name"
+    value = "Dummy Offering"
+  }
+}
+
+resource "cloudstack_instance_backup_policy" "foo" {
+  virtual_machine_id = cloudstack_instance.foobar.id
+  backup_offering_id = data.cloudstack_backup_offering.foo.id
+
+  schedule {
+    interval_type = "DAILY"
+    schedule = "30:03"
+    timezone = "UTC"
+  }
+
+  schedule {
+    interval_type = "WEEKLY"
+    schedule = "00:04:1"
+    timezone = "UTC"
+    max_backups = 4
+  }
+}`
@@ -0,0 +1,67 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestResourceCloudStackInstanceStateUpgradeV0(t *testing.T) {
+	cases := []struct {
+		State, Expected map[string]interface{}
+	}{
+		// UEFI instance
+		{
+			State: map[string]interface{}{
+				"name": "foo",
+				"uefi": true,
+			},
+			Expected: map[string]interface{}{
+				"name":      "foo",
+				"uefi":      true,
+				"boot_type": "UEFI",
+				"boot_mode": "Legacy",
+			},
+		},
+
+		// BIOS instance
+		{
+			State: map[string]interface{}{
+				"name": "foo",
+				"uefi": false,
+			},
+			Expected: map[string]interface{}{
+				"name": "foo",
+			},
+		},
+	}
+
+	for i, tc := range cases {
+		actual, err := resourceCloudStackInstanceStateUpgradeV0(context.Background(), tc.State, nil)
+		if err != nil {
+			t.Fatalf("%d: unexpected error: %s", i, err)
+		}
+		if !reflect.DeepEqual(actual, tc.Expected) {
+			t.Fatalf("%d: bad state: %#v", i, actual)
+		}
+	}
+}
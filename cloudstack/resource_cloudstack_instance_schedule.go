@@ -0,0 +1,260 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceCloudStackInstanceSchedule() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceCloudStackInstanceScheduleCreate,
+		Read:   resourceCloudStackInstanceScheduleRead,
+		Update: resourceCloudStackInstanceScheduleUpdate,
+		Delete: resourceCloudStackInstanceScheduleDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceCloudStackInstanceScheduleImportContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"virtual_machine_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"action": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					"START", "STOP", "REBOOT", "FORCE_STOP", "FORCE_REBOOT",
+				}, false),
+			},
+
+			"schedule": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			"timezone": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+
+			"start_date": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				DiffSuppressFunc: suppressScheduleDateDiff,
+			},
+
+			"end_date": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: suppressScheduleDateDiff,
+			},
+
+			"enabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+		},
+	}
+}
+
+func resourceCloudStackInstanceScheduleCreate(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	virtualmachineid := d.Get("virtual_machine_id").(string)
+
+	// Create a new parameter struct
+	p := cs.VirtualMachine.NewCreateVMScheduleParams(
+		d.Get("action").(string),
+		d.Get("schedule").(string),
+		d.Get("timezone").(string),
+		virtualmachineid,
+	)
+
+	if description, ok := d.GetOk("description"); ok {
+		p.SetDescription(description.(string))
+	}
+
+	if startdate, ok := d.GetOk("start_date"); ok {
+		p.SetStartdate(startdate.(string))
+	}
+
+	if enddate, ok := d.GetOk("end_date"); ok {
+		p.SetEnddate(enddate.(string))
+	}
+
+	p.SetEnabled(d.Get("enabled").(bool))
+
+	log.Printf("[DEBUG] Creating schedule for virtual machine %s", virtualmachineid)
+	r, err := cs.VirtualMachine.CreateVMSchedule(p)
+	if err != nil {
+		return fmt.Errorf("Error creating schedule for virtual machine %s: %s", virtualmachineid, err)
+	}
+
+	d.SetId(r.Id)
+
+	return resourceCloudStackInstanceScheduleRead(d, meta)
+}
+
+func resourceCloudStackInstanceScheduleRead(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Get the schedule details
+	s, count, err := cs.VirtualMachine.GetVMScheduleByID(d.Id(), d.Get("virtual_machine_id").(string))
+	if err != nil {
+		if count == 0 {
+			log.Printf("[DEBUG] Instance schedule %s does no longer exist", d.Id())
+			d.SetId("")
+			return nil
+		}
+
+		return err
+	}
+
+	d.Set("virtual_machine_id", s.Virtualmachineid)
+	d.Set("action", s.Action)
+	d.Set("schedule", s.Schedule)
+	d.Set("timezone", s.Timezone)
+	d.Set("description", s.Description)
+	d.Set("start_date", s.Startdate)
+	d.Set("end_date", s.Enddate)
+	d.Set("enabled", s.Enabled)
+
+	return nil
+}
+
+func resourceCloudStackInstanceScheduleUpdate(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Create a new parameter struct
+	p := cs.VirtualMachine.NewUpdateVMScheduleParams(d.Id())
+	p.SetSchedule(d.Get("schedule").(string))
+	p.SetTimezone(d.Get("timezone").(string))
+	p.SetEnabled(d.Get("enabled").(bool))
+
+	if d.HasChange("description") {
+		p.SetDescription(d.Get("description").(string))
+	}
+
+	if d.HasChange("start_date") {
+		p.SetStartdate(d.Get("start_date").(string))
+	}
+
+	if d.HasChange("end_date") {
+		p.SetEnddate(d.Get("end_date").(string))
+	}
+
+	log.Printf("[DEBUG] Updating instance schedule %s", d.Id())
+	if _, err := cs.VirtualMachine.UpdateVMSchedule(p); err != nil {
+		return fmt.Errorf("Error updating instance schedule %s: %s", d.Id(), err)
+	}
+
+	return resourceCloudStackInstanceScheduleRead(d, meta)
+}
+
+func resourceCloudStackInstanceScheduleDelete(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Create a new parameter struct
+	p := cs.VirtualMachine.NewDeleteVMScheduleParams(d.Get("virtual_machine_id").(string))
+	p.SetId(d.Id())
+
+	log.Printf("[INFO] Deleting instance schedule: %s", d.Id())
+	if _, err := cs.VirtualMachine.DeleteVMSchedule(p); err != nil {
+		// This is a very poor way to be told the ID does no longer exist :(
+		if strings.Contains(err.Error(), fmt.Sprintf(
+			"Invalid parameter id value=%s due to incorrect long value format, "+
+				"or entity does not exist", d.Id())) {
+			return nil
+		}
+
+		return fmt.Errorf("Error deleting instance schedule %s: %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+// Schedules can only be listed for a virtual machine, so the import ID
+// contains both the virtual machine ID and the schedule ID
+func resourceCloudStackInstanceScheduleImportContext(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	s := strings.SplitN(d.Id(), "/", 2)
+	if len(s) != 2 {
+		return nil, fmt.Errorf(
+			"Invalid import ID %q, expected <VIRTUAL MACHINE ID>/<SCHEDULE ID>", d.Id())
+	}
+
+	d.Set("virtual_machine_id", s[0])
+	d.SetId(s[1])
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// suppressScheduleDateDiff suppresses diffs between a configured date, which
+// is in the timezone of the schedule, and the same date as returned by
+// CloudStack
+func suppressScheduleDateDiff(k, old, new string, d *schema.ResourceData) bool {
+	if old == "" || new == "" {
+		return old == new
+	}
+
+	loc, err := time.LoadLocation(d.Get("timezone").(string))
+	if err != nil {
+		loc = time.UTC
+	}
+
+	parse := func(v string) (time.Time, error) {
+		if t, err := time.Parse("2006-01-02T15:04:05-0700", v); err == nil {
+			return t, nil
+		}
+		return time.ParseInLocation("2006-01-02 15:04:05", v, loc)
+	}
+
+	o, err := parse(old)
+	if err != nil {
+		return false
+	}
+
+	n, err := parse(new)
+	if err != nil {
+		return false
+	}
+
+	return o.Equal(n)
+}
@@ -0,0 +1,192 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccCloudStackInstanceSchedule_basic(t *testing.T) {
+	var schedule cloudstack.VMSchedule
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackInstanceScheduleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackInstanceSchedule_basic("0 20 * * 1-5", true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackInstanceScheduleExists(
+						"cloudstack_instance_schedule.foo", &schedule),
+					resource.TestCheckResourceAttr(
+						"cloudstack_instance_schedule.foo", "action", "STOP"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_instance_schedule.foo", "enabled", "true"),
+				),
+			},
+
+			{
+				Config: testAccCloudStackInstanceSchedule_basic("30 21 * * 1-5", false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackInstanceScheduleExists(
+						"cloudstack_instance_schedule.foo", &schedule),
+					resource.TestCheckResourceAttr(
+						"cloudstack_instance_schedule.foo", "schedule", "30 21 * * 1-5"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_instance_schedule.foo", "enabled", "false"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudStackInstanceSchedule_import(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackInstanceScheduleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackInstanceSchedule_basic("0 20 * * 1-5", true),
+			},
+
+			{
+				ResourceName:      "cloudstack_instance_schedule.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					rs := s.RootModule().Resources["cloudstack_instance_schedule.foo"]
+					return rs.Primary.Attributes["virtual_machine_id"] + "/" + rs.Primary.ID, nil
+				},
+			},
+		},
+	})
+}
+
+func TestSuppressScheduleDateDiff(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCloudStackInstanceSchedule().Schema, map[string]interface{}{
+		"timezone": "Europe/Amsterdam",
+	})
+
+	cases := []struct {
+		old, new string
+		suppress bool
+	}{
+		{"2030-01-01T09:00:00+0000", "2030-01-01 10:00:00", true},
+		{"2030-01-01T10:00:00+0000", "2030-01-01 10:00:00", false},
+		{"2030-01-01T09:00:00+0000", "2030-01-01T09:00:00+0000", true},
+		{"", "2030-01-01 10:00:00", false},
+		{"2030-01-01T09:00:00+0000", "invalid", false},
+	}
+
+	for _, c := range cases {
+		if got := suppressScheduleDateDiff("start_date", c.old, c.new, d); got != c.suppress {
+			t.Errorf("suppressScheduleDateDiff(%q, %q) = %t, want %t", c.old, c.new, got, c.suppress)
+		}
+	}
+}
+
+func testAccCheckCloudStackInstanceScheduleExists(
+	n string, schedule *cloudstack.VMSchedule) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No instance schedule ID is set")
+		}
+
+		cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+		sched, _, err := cs.VirtualMachine.GetVMScheduleByID(
+			rs.Primary.ID, rs.Primary.Attributes["virtual_machine_id"])
+		if err != nil {
+			return err
+		}
+
+		if sched.Id != rs.Primary.ID {
+			return fmt.Errorf("Instance schedule not found")
+		}
+
+		*schedule = *sched
+
+		return nil
+	}
+}
+
+func testAccCheckCloudStackInstanceScheduleDestroy(s *terraform.State) error {
+	cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "cloudstack_instance_schedule" {
+			continue
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No instance schedule ID is set")
+		}
+
+		_, count, err := cs.VirtualMachine.GetVMScheduleByID(
+			rs.Primary.ID, rs.Primary.Attributes["virtual_machine_id"])
+		if err == nil && count > 0 {
+			return fmt.Errorf("Instance schedule %s still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+func testAccCloudStackInstanceSchedule_basic(schedule string, enabled bool) string {
+	return fmt.Sprintf(`
+resource "cloudstack_network" "foo" {
+  name = "terraform-network"
+  display_text = "terraform-network"
+  cidr = "10.1.1.0/24"
+  network_offering = "DefaultIsolatedNetworkOfferingWithSourceNatService"
+  zone = "Sandbox-simulator"
+}
+
+resource "cloudstack_instance" "foobar" {
+  name = "terraform-test"
+  display_name = "terraform-test"
+  service_offering= "Small Instance"
+  network_id = cloudstack_network.foo.id
+  template = "CentOS 5.6 (64-bit) no GUI (Simulator)"
+  zone = "Sandbox-simulator"
+  expunge = true
+}
+
+resource "cloudstack_instance_schedule" "foo" {
+  virtual_machine_id = cloudstack_instance.foobar.id
+  action = "STOP"
+  schedule = "%s"
+  timezone = "UTC"
+  description = "Stop the instance in the evening"
+  enabled = %t
+}`, schedule, enabled)
+}
@@ -20,10 +20,17 @@
 package cloudstack
 
 import (
+	"bytes"
+	"encoding/base64"
 	"fmt"
+	"io"
+	"os"
+	"reflect"
 	"regexp"
+	"strings"
 	"testing"
 
+	"github.com/ProtonMail/go-crypto/openpgp"
 	"github.com/apache/cloudstack-go/v2/cloudstack"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
@@ -45,6 +52,12 @@ func TestAccCloudStackInstance_basic(t *testing.T) {
 					testAccCheckCloudStackInstanceAttributes(&instance),
 					resource.TestCheckResourceAttr(
 						"cloudstack_instance.foobar", "user_data", "0cf3dcdc356ec8369494cb3991985ecd5296cdd5"),
+					resource.TestCheckResourceAttrSet(
+						"cloudstack_instance.foobar", "host_id"),
+					resource.TestCheckResourceAttrSet(
+						"cloudstack_instance.foobar", "cluster_id"),
+					resource.TestCheckResourceAttrSet(
+						"cloudstack_instance.foobar", "pod_id"),
 					testAccCheckResourceTags(&instance),
 				),
 			},
@@ -109,6 +122,276 @@ func TestAccCloudStackInstance_update(t *testing.T) {
 	})
 }
 
+func TestAccCloudStackInstance_rootDiskResize(t *testing.T) {
+	var instance cloudstack.VirtualMachine
+	var resized cloudstack.VirtualMachine
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackInstance_rootDiskSize(20),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackInstanceExists(
+						"cloudstack_instance.foobar", &instance),
+					resource.TestCheckResourceAttr(
+						"cloudstack_instance.foobar", "root_disk_size", "20"),
+				),
+			},
+
+			{
+				Config: testAccCloudStackInstance_rootDiskSize(30),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackInstanceExists(
+						"cloudstack_instance.foobar", &resized),
+					testAccCheckCloudStackInstanceNotRecreated(&instance, &resized),
+					resource.TestCheckResourceAttr(
+						"cloudstack_instance.foobar", "root_disk_size", "30"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudStackInstance_migrate(t *testing.T) {
+	var instance cloudstack.VirtualMachine
+	var migrated cloudstack.VirtualMachine
+
+	// The instance is deployed on any host, so the host to migrate to needs
+	// to be supplied
+	hostid := os.Getenv("CLOUDSTACK_HOST_ID")
+	if hostid == "" {
+		t.Skip("This test requires CLOUDSTACK_HOST_ID to be set")
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackInstance_host(""),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackInstanceExists(
+						"cloudstack_instance.foobar", &instance),
+					resource.TestCheckResourceAttrSet(
+						"cloudstack_instance.foobar", "host_id"),
+				),
+			},
+
+			{
+				Config: testAccCloudStackInstance_host(hostid),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackInstanceExists(
+						"cloudstack_instance.foobar", &migrated),
+					testAccCheckCloudStackInstanceNotRecreated(&instance, &migrated),
+					resource.TestCheckResourceAttr(
+						"cloudstack_instance.foobar", "host_id", hostid),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudStackInstance_securityGroups(t *testing.T) {
+	var instance cloudstack.VirtualMachine
+	var updated cloudstack.VirtualMachine
+
+	// Security groups require a zone with security groups enabled
+	zone := os.Getenv("CLOUDSTACK_SECURITY_GROUP_ZONE")
+	if zone == "" {
+		t.Skip("This test requires CLOUDSTACK_SECURITY_GROUP_ZONE to be set")
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackInstance_securityGroups(zone, "cloudstack_security_group.foo.name"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackInstanceExists(
+						"cloudstack_instance.foobar", &instance),
+					resource.TestCheckResourceAttr(
+						"cloudstack_instance.foobar", "security_group_names.#", "1"),
+				),
+			},
+
+			{
+				Config: testAccCloudStackInstance_securityGroups(
+					zone, "cloudstack_security_group.foo.name", "cloudstack_security_group.bar.name"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackInstanceExists(
+						"cloudstack_instance.foobar", &updated),
+					testAccCheckCloudStackInstanceNotRecreated(&instance, &updated),
+					resource.TestCheckResourceAttr(
+						"cloudstack_instance.foobar", "security_group_names.#", "2"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudStackInstance_details(t *testing.T) {
+	var instance cloudstack.VirtualMachine
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackInstance_details("virtio"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackInstanceExists(
+						"cloudstack_instance.foobar", &instance),
+					testAccCheckCloudStackInstanceDetail(&instance, "rootDiskController", "virtio"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_instance.foobar", "details.rootDiskController", "virtio"),
+				),
+			},
+
+			{
+				Config: testAccCloudStackInstance_details("scsi"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackInstanceExists(
+						"cloudstack_instance.foobar", &instance),
+					testAccCheckCloudStackInstanceDetail(&instance, "rootDiskController", "scsi"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_instance.foobar", "details.rootDiskController", "scsi"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudStackInstance_extraConfig(t *testing.T) {
+	var instance cloudstack.VirtualMachine
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackInstance_extraConfig("2"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackInstanceExists(
+						"cloudstack_instance.foobar", &instance),
+					resource.TestCheckResourceAttr(
+						"cloudstack_instance.foobar", "extra_config", "<vcpu>2</vcpu>"),
+				),
+			},
+
+			{
+				Config: testAccCloudStackInstance_extraConfig("4"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackInstanceExists(
+						"cloudstack_instance.foobar", &instance),
+					resource.TestCheckResourceAttr(
+						"cloudstack_instance.foobar", "extra_config", "<vcpu>4</vcpu>"),
+				),
+			},
+		},
+	})
+}
+
+func TestKvmExtraConfigDetails(t *testing.T) {
+	cases := map[string]map[string]string{
+		"": {},
+		"<vcpu>2</vcpu>": {
+			"extraconfig-1": "<vcpu>2</vcpu>",
+		},
+		"memoryBacking:\n<memoryBacking><hugepages/></memoryBacking>\n\n<vcpu>2</vcpu>": {
+			"extraconfig-memoryBacking": "<memoryBacking><hugepages/></memoryBacking>",
+			"extraconfig-2":             "<vcpu>2</vcpu>",
+		},
+	}
+
+	for config, expected := range cases {
+		if details := kvmExtraConfigDetails(config); !reflect.DeepEqual(details, expected) {
+			t.Errorf("kvmExtraConfigDetails(%q) = %v, expected %v", config, details, expected)
+		}
+	}
+}
+
+func TestAccCloudStackInstance_dataDisk(t *testing.T) {
+	var instance cloudstack.VirtualMachine
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackInstance_dataDisk,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackInstanceExists(
+						"cloudstack_instance.foobar", &instance),
+					resource.TestCheckResourceAttr(
+						"cloudstack_instance.foobar", "root_disk_offering", "Small"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_instance.foobar", "data_disk.0.disk_offering", "Custom"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_instance.foobar", "data_disk.0.size", "10"),
+					resource.TestCheckResourceAttrSet(
+						"cloudstack_instance.foobar", "data_disk.0.id"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudStackInstance_passwordReset(t *testing.T) {
+	var instance cloudstack.VirtualMachine
+	var encrypted string
+	entity, key := testPGPKey(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackInstance_passwordReset(key, ""),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackInstanceExists(
+						"cloudstack_instance.foobar", &instance),
+					testAccCheckCloudStackInstancePassword(
+						"cloudstack_instance.foobar", entity, &encrypted),
+				),
+			},
+
+			{
+				Config: testAccCloudStackInstance_passwordReset(key, "reset-1"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackInstanceExists(
+						"cloudstack_instance.foobar", &instance),
+					testAccCheckCloudStackInstancePassword(
+						"cloudstack_instance.foobar", entity, &encrypted),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudStackInstance_isoWithoutHypervisor(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccCloudStackInstance_isoWithoutHypervisor,
+				ExpectError: regexp.MustCompile("hypervisor must be set when deploying from an ISO"),
+			},
+		},
+	})
+}
+
 func TestAccCloudStackInstance_fixedIP(t *testing.T) {
 	var instance cloudstack.VirtualMachine
 
@@ -248,7 +531,7 @@ func TestAccCloudStackInstance_import(t *testing.T) {
 				ResourceName:            "cloudstack_instance.foobar",
 				ImportState:             true,
 				ImportStateVerify:       true,
-				ImportStateVerifyIgnore: []string{"expunge", "user_data", "uefi"},
+				ImportStateVerifyIgnore: []string{"expunge", "user_data", "uefi", "root_disk_shrink_ok", "boot_into_setup"},
 			},
 		},
 	})
@@ -269,7 +552,7 @@ func TestAccCloudStackInstance_importProject(t *testing.T) {
 				ImportState:             true,
 				ImportStateIdPrefix:     "terraform/",
 				ImportStateVerify:       true,
-				ImportStateVerifyIgnore: []string{"expunge", "user_data", "uefi"},
+				ImportStateVerifyIgnore: []string{"expunge", "user_data", "uefi", "root_disk_shrink_ok", "boot_into_setup"},
 			},
 		},
 	})
@@ -371,6 +654,71 @@ func testAccCheckCloudStackInstanceRenamedAndResized(
 	}
 }
 
+func testAccCheckCloudStackInstanceDetail(
+	instance *cloudstack.VirtualMachine, key, value string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if instance.Details[key] != value {
+			return fmt.Errorf("Bad detail %s: %s", key, instance.Details[key])
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckCloudStackInstanceNotRecreated(
+	before, after *cloudstack.VirtualMachine) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if before.Id != after.Id {
+			return fmt.Errorf("Instance was recreated: %s != %s", before.Id, after.Id)
+		}
+
+		return nil
+	}
+}
+
+// testAccCheckCloudStackInstancePassword checks that the encrypted password
+// can be decrypted and differs from the previously encrypted password
+func testAccCheckCloudStackInstancePassword(
+	n string, entity *openpgp.Entity, previous *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		encrypted := rs.Primary.Attributes["encrypted_password"]
+		if encrypted == "" {
+			return fmt.Errorf("No encrypted password is set")
+		}
+
+		if encrypted == *previous {
+			return fmt.Errorf("Encrypted password did not change")
+		}
+		*previous = encrypted
+
+		b, err := base64.StdEncoding.DecodeString(encrypted)
+		if err != nil {
+			return err
+		}
+
+		md, err := openpgp.ReadMessage(bytes.NewReader(b), openpgp.EntityList{entity}, nil, nil)
+		if err != nil {
+			return err
+		}
+
+		password, err := io.ReadAll(md.UnverifiedBody)
+		if err != nil {
+			return err
+		}
+
+		if len(password) == 0 {
+			return fmt.Errorf("Decrypted password is empty")
+		}
+
+		return nil
+	}
+}
+
 func testAccCheckCloudStackInstanceDestroy(s *terraform.State) error {
 	cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
 
@@ -455,6 +803,183 @@ resource "cloudstack_instance" "foobar" {
   expunge = true
 }`
 
+func testAccCloudStackInstance_rootDiskSize(size int) string {
+	return fmt.Sprintf(`
+resource "cloudstack_network" "foo" {
+  name = "terraform-network"
+  display_text = "terraform-network"
+  cidr = "10.1.1.0/24"
+  network_offering = "DefaultIsolatedNetworkOfferingWithSourceNatService"
+  zone = "Sandbox-simulator"
+}
+
+resource "cloudstack_instance" "foobar" {
+  name = "terraform-test"
+  display_name = "terraform-test"
+  service_offering= "Small Instance"
+  network_id = cloudstack_network.foo.id
+  template = "CentOS 5.6 (64-bit) no GUI (Simulator)"
+  zone = "Sandbox-simulator"
+  root_disk_size = %d
+  expunge = true
+}`, size)
+}
+
+func testAccCloudStackInstance_host(hostid string) string {
+	host := ""
+	if hostid != "" {
+		host = fmt.Sprintf("host_id = %q", hostid)
+	}
+
+	return fmt.Sprintf(`
+resource "cloudstack_network" "foo" {
+  name = "terraform-network"
+  display_text = "terraform-network"
+  cidr = "10.1.1.0/24"
+  network_offering = "DefaultIsolatedNetworkOfferingWithSourceNatService"
+  zone = "Sandbox-simulator"
+}
+
+resource "cloudstack_instance" "foobar" {
+  name = "terraform-test"
+  display_name = "terraform-test"
+  service_offering= "Small Instance"
+  network_id = cloudstack_network.foo.id
+  template = "CentOS 5.6 (64-bit) no GUI (Simulator)"
+  zone = "Sandbox-simulator"
+  expunge = true
+  %s
+}`, host)
+}
+
+func testAccCloudStackInstance_securityGroups(zone string, groups ...string) string {
+	return fmt.Sprintf(`
+resource "cloudstack_security_group" "foo" {
+  name = "terraform-security-group-foo"
+  description = "terraform-security-group-foo"
+}
+
+resource "cloudstack_security_group" "bar" {
+  name = "terraform-security-group-bar"
+  description = "terraform-security-group-bar"
+}
+
+resource "cloudstack_instance" "foobar" {
+  name = "terraform-test"
+  display_name = "terraform-test"
+  service_offering= "Small Instance"
+  template = "CentOS 5.6 (64-bit) no GUI (Simulator)"
+  zone = "%s"
+  security_group_names = [%s]
+  expunge = true
+}`, zone, strings.Join(groups, ", "))
+}
+
+func testAccCloudStackInstance_details(controller string) string {
+	return fmt.Sprintf(`
+resource "cloudstack_network" "foo" {
+  name = "terraform-network"
+  display_text = "terraform-network"
+  cidr = "10.1.1.0/24"
+  network_offering = "DefaultIsolatedNetworkOfferingWithSourceNatService"
+  zone = "Sandbox-simulator"
+}
+
+resource "cloudstack_instance" "foobar" {
+  name = "terraform-test"
+  display_name = "terraform-test"
+  service_offering= "Small Instance"
+  network_id = cloudstack_network.foo.id
+  template = "CentOS 5.6 (64-bit) no GUI (Simulator)"
+  zone = "Sandbox-simulator"
+  details = {
+    rootDiskController = "%s"
+  }
+  expunge = true
+}`, controller)
+}
+
+func testAccCloudStackInstance_extraConfig(vcpus string) string {
+	return fmt.Sprintf(`
+resource "cloudstack_network" "foo" {
+  name = "terraform-network"
+  display_text = "terraform-network"
+  cidr = "10.1.1.0/24"
+  network_offering = "DefaultIsolatedNetworkOfferingWithSourceNatService"
+  zone = "Sandbox-simulator"
+}
+
+resource "cloudstack_instance" "foobar" {
+  name = "terraform-test"
+  display_name = "terraform-test"
+  service_offering= "Small Instance"
+  network_id = cloudstack_network.foo.id
+  template = "CentOS 5.6 (64-bit) no GUI (Simulator)"
+  zone = "Sandbox-simulator"
+  extra_config = "<vcpu>%s</vcpu>"
+  expunge = true
+}`, vcpus)
+}
+
+const testAccCloudStackInstance_dataDisk = `
+resource "cloudstack_network" "foo" {
+  name = "terraform-network"
+  display_text = "terraform-network"
+  cidr = "10.1.1.0/24"
+  network_offering = "DefaultIsolatedNetworkOfferingWithSourceNatService"
+  zone = "Sandbox-simulator"
+}
+
+resource "cloudstack_instance" "foobar" {
+  name = "terraform-test"
+  display_name = "terraform-test"
+  service_offering= "Small Instance"
+  network_id = cloudstack_network.foo.id
+  template = "CentOS 5.6 (64-bit) no GUI (Simulator)"
+  zone = "Sandbox-simulator"
+  root_disk_offering = "Small"
+
+  data_disk {
+    disk_offering = "Custom"
+    size = 10
+  }
+
+  expunge = true
+}`
+
+func testAccCloudStackInstance_passwordReset(key, trigger string) string {
+	return fmt.Sprintf(`
+resource "cloudstack_network" "foo" {
+  name = "terraform-network"
+  display_text = "terraform-network"
+  cidr = "10.1.1.0/24"
+  network_offering = "DefaultIsolatedNetworkOfferingWithSourceNatService"
+  zone = "Sandbox-simulator"
+}
+
+resource "cloudstack_instance" "foobar" {
+  name = "terraform-test"
+  display_name = "terraform-test"
+  service_offering= "Small Instance"
+  network_id = cloudstack_network.foo.id
+  template = "CentOS 5.6 (64-bit) no GUI (Simulator)"
+  zone = "Sandbox-simulator"
+  pgp_key = "%s"
+  password_reset_trigger = "%s"
+  expunge = true
+}`, key, trigger)
+}
+
+const testAccCloudStackInstance_isoWithoutHypervisor = `
+resource "cloudstack_instance" "foobar" {
+  name = "terraform-test"
+  service_offering= "Small Instance"
+  iso = "xs-tools.iso"
+  root_disk_offering = "Small"
+  zone = "Sandbox-simulator"
+  expunge = true
+}`
+
 const testAccCloudStackInstance_fixedIP = `
 resource "cloudstack_network" "foo" {
   name = "terraform-network"
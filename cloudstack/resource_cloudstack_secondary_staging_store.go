@@ -0,0 +1,176 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceCloudStackSecondaryStagingStore() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceCloudStackSecondaryStagingStoreCreate,
+		Read:   resourceCloudStackSecondaryStagingStoreRead,
+		Delete: resourceCloudStackSecondaryStagingStoreDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: importStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"url": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"zone": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"storage_provider": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"scope": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressCaseDiff,
+			},
+
+			"details": {
+				Type:      schema.TypeMap,
+				Optional:  true,
+				ForceNew:  true,
+				Sensitive: true,
+				Elem:      &schema.Schema{Type: schema.TypeString},
+			},
+
+			"name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"protocol": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceCloudStackSecondaryStagingStoreCreate(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	url := d.Get("url").(string)
+
+	// Create a new parameter struct
+	p := cs.ImageStore.NewCreateSecondaryStagingStoreParams(url)
+
+	if zone, ok := d.GetOk("zone"); ok {
+		// Retrieve the zone ID
+		zoneid, e := retrieveID(cs, "zone", zone.(string))
+		if e != nil {
+			return e.Error()
+		}
+		p.SetZoneid(zoneid)
+	}
+
+	if provider, ok := d.GetOk("storage_provider"); ok {
+		p.SetProvider(provider.(string))
+	}
+
+	if scope, ok := d.GetOk("scope"); ok {
+		p.SetScope(scope.(string))
+	}
+
+	if details, ok := d.GetOk("details"); ok {
+		p.SetDetails(stringMapFromSchema(details.(map[string]interface{})))
+	}
+
+	log.Printf("[DEBUG] Creating secondary staging store %s", url)
+	r, err := cs.ImageStore.CreateSecondaryStagingStore(p)
+	if err != nil {
+		return fmt.Errorf("Error creating secondary staging store %s: %s", url, err)
+	}
+
+	d.SetId(r.Id)
+
+	return resourceCloudStackSecondaryStagingStoreRead(d, meta)
+}
+
+func resourceCloudStackSecondaryStagingStoreRead(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Get the secondary staging store details
+	s, count, err := cs.ImageStore.GetSecondaryStagingStoreByID(d.Id())
+	if err != nil {
+		if count == 0 {
+			log.Printf("[DEBUG] Secondary staging store %s does no longer exist", d.Id())
+			d.SetId("")
+			return nil
+		}
+
+		return err
+	}
+
+	d.Set("url", s.Url)
+	d.Set("storage_provider", s.Providername)
+	d.Set("scope", s.Scope)
+	d.Set("name", s.Name)
+	d.Set("protocol", s.Protocol)
+
+	if s.Zoneid != "" {
+		setValueOrID(d, "zone", s.Zonename, s.Zoneid)
+	}
+
+	return nil
+}
+
+func resourceCloudStackSecondaryStagingStoreDelete(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Create a new parameter struct
+	p := cs.ImageStore.NewDeleteSecondaryStagingStoreParams(d.Id())
+
+	log.Printf("[INFO] Deleting secondary staging store: %s", d.Id())
+	if _, err := cs.ImageStore.DeleteSecondaryStagingStore(p); err != nil {
+		// This is a very poor way to be told the ID does no longer exist :(
+		if strings.Contains(err.Error(), fmt.Sprintf(
+			"Invalid parameter id value=%s due to incorrect long value format, "+
+				"or entity does not exist", d.Id())) {
+			return nil
+		}
+
+		return fmt.Errorf("Error deleting secondary staging store %s: %s", d.Id(), err)
+	}
+
+	return nil
+}
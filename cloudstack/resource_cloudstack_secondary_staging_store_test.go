@@ -0,0 +1,126 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccCloudStackSecondaryStagingStore_basic(t *testing.T) {
+	var store cloudstack.SecondaryStagingStore
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackSecondaryStagingStoreDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackSecondaryStagingStore_basic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackSecondaryStagingStoreExists(
+						"cloudstack_secondary_staging_store.foo", &store),
+					resource.TestCheckResourceAttr(
+						"cloudstack_secondary_staging_store.foo", "protocol", "nfs"),
+					resource.TestCheckResourceAttrSet(
+						"cloudstack_secondary_staging_store.foo", "name"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudStackSecondaryStagingStore_import(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackSecondaryStagingStoreDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackSecondaryStagingStore_basic,
+			},
+
+			{
+				ResourceName:      "cloudstack_secondary_staging_store.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckCloudStackSecondaryStagingStoreExists(
+	n string, store *cloudstack.SecondaryStagingStore) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No secondary staging store ID is set")
+		}
+
+		cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+		i, _, err := cs.ImageStore.GetSecondaryStagingStoreByID(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		if i.Id != rs.Primary.ID {
+			return fmt.Errorf("Secondary staging store not found")
+		}
+
+		*store = *i
+
+		return nil
+	}
+}
+
+func testAccCheckCloudStackSecondaryStagingStoreDestroy(s *terraform.State) error {
+	cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "cloudstack_secondary_staging_store" {
+			continue
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No secondary staging store ID is set")
+		}
+
+		_, _, err := cs.ImageStore.GetSecondaryStagingStoreByID(rs.Primary.ID)
+		if err == nil {
+			return fmt.Errorf("Secondary staging store %s still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+const testAccCloudStackSecondaryStagingStore_basic = `
+resource "cloudstack_secondary_staging_store" "foo" {
+  url = "nfs://10.147.28.6/export/home/sandbox/terraform-staging"
+  zone = "Sandbox-simulator"
+}`
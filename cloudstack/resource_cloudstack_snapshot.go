@@ -0,0 +1,307 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceCloudStackSnapshot() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceCloudStackSnapshotCreate,
+		Read:   resourceCloudStackSnapshotRead,
+		Update: resourceCloudStackSnapshotUpdate,
+		Delete: resourceCloudStackSnapshotDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: importStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"volume_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"name": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"quiescevm": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+				ForceNew: true,
+			},
+
+			"location_type": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					"PRIMARY", "SECONDARY",
+				}, true),
+				DiffSuppressFunc: suppressCaseDiff,
+			},
+
+			"zone_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"zone_ids": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Set:      schema.HashString,
+			},
+
+			"project": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"snapshot_type": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"state": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"physical_size": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+
+			"virtual_size": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+
+			"tags": tagsSchema(),
+		},
+	}
+}
+
+func resourceCloudStackSnapshotCreate(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	volumeid := d.Get("volume_id").(string)
+
+	// Retrieve the volume, so we know in which zone the snapshot is created
+	v, _, err := cs.Volume.GetVolumeByID(
+		volumeid,
+		cloudstack.WithProject(d.Get("project").(string)),
+	)
+	if err != nil {
+		return fmt.Errorf("Error retrieving volume %s: %s", volumeid, err)
+	}
+
+	// Create a new parameter struct
+	p := cs.Snapshot.NewCreateSnapshotParams(volumeid)
+
+	if name, ok := d.GetOk("name"); ok {
+		p.SetName(name.(string))
+	}
+
+	if locationtype, ok := d.GetOk("location_type"); ok {
+		p.SetLocationtype(strings.ToUpper(locationtype.(string)))
+	}
+
+	p.SetQuiescevm(d.Get("quiescevm").(bool))
+
+	if zoneids := snapshotCopyZoneIDs(d.Get("zone_ids").(*schema.Set), v.Zoneid); len(zoneids) > 0 {
+		p.SetZoneids(zoneids)
+	}
+
+	log.Printf("[DEBUG] Creating snapshot of volume %s", volumeid)
+	r, err := cs.Snapshot.CreateSnapshot(p)
+	if err != nil {
+		return fmt.Errorf("Error creating snapshot of volume %s: %s", volumeid, err)
+	}
+
+	d.SetId(r.Id)
+	d.Set("zone_id", v.Zoneid)
+
+	// Set tags if necessary
+	if err = setTags(cs, d, "Snapshot"); err != nil {
+		return fmt.Errorf("Error setting tags on snapshot %s: %s", r.Id, err)
+	}
+
+	return resourceCloudStackSnapshotRead(d, meta)
+}
+
+func resourceCloudStackSnapshotRead(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Create a new parameter struct
+	p := cs.Snapshot.NewListSnapshotsParams()
+	p.SetId(d.Id())
+
+	// List a snapshot per zone it is available in
+	p.SetShowunique(false)
+
+	// If there is a project supplied, we retrieve and set the project id
+	if err := setProjectid(p, cs, d); err != nil {
+		return err
+	}
+
+	l, err := cs.Snapshot.ListSnapshots(p)
+	if err != nil {
+		return err
+	}
+
+	if l.Count == 0 {
+		log.Printf("[DEBUG] Snapshot %s does no longer exist", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	s := l.Snapshots[0]
+
+	// When importing, the zone of the snapshot is the zone of its volume
+	zoneid := d.Get("zone_id").(string)
+	if zoneid == "" {
+		zoneid = s.Zoneid
+		if v, count, err := cs.Volume.GetVolumeByID(s.Volumeid, cloudstack.WithProject(s.Projectid)); err == nil && count == 1 {
+			zoneid = v.Zoneid
+		}
+	}
+
+	// Use the snapshot in the source zone, as the copies can have another state
+	for _, snapshot := range l.Snapshots {
+		if snapshot.Zoneid == zoneid {
+			s = snapshot
+			break
+		}
+	}
+
+	zoneids := &schema.Set{F: schema.HashString}
+	for _, snapshot := range l.Snapshots {
+		if snapshot.Zoneid != "" && snapshot.Zoneid != zoneid {
+			zoneids.Add(snapshot.Zoneid)
+		}
+	}
+
+	d.Set("volume_id", s.Volumeid)
+	d.Set("name", s.Name)
+	d.Set("location_type", s.Locationtype)
+	d.Set("zone_id", zoneid)
+	d.Set("zone_ids", zoneids)
+	d.Set("snapshot_type", s.Snapshottype)
+	d.Set("state", s.State)
+	d.Set("physical_size", s.Physicalsize)
+	d.Set("virtual_size", s.Virtualsize)
+	d.Set("tags", tagsToMap(s.Tags))
+
+	setValueOrID(d, "project", s.Project, s.Projectid)
+
+	return nil
+}
+
+func resourceCloudStackSnapshotUpdate(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Check if the zones have changed and if so, copy the snapshot to the new
+	// zones and delete the copies from the zones that are removed
+	if d.HasChange("zone_ids") {
+		o, n := d.GetChange("zone_ids")
+		zoneid := d.Get("zone_id").(string)
+
+		if zoneids := snapshotCopyZoneIDs(n.(*schema.Set).Difference(o.(*schema.Set)), zoneid); len(zoneids) > 0 {
+			p := cs.Snapshot.NewCopySnapshotParams(d.Id())
+			p.SetSourcezoneid(zoneid)
+			p.SetDestzoneids(zoneids)
+
+			log.Printf("[DEBUG] Copying snapshot %s to zones %v", d.Id(), zoneids)
+			if _, err := cs.Snapshot.CopySnapshot(p); err != nil {
+				return fmt.Errorf("Error copying snapshot %s: %s", d.Id(), err)
+			}
+		}
+
+		for _, z := range snapshotCopyZoneIDs(o.(*schema.Set).Difference(n.(*schema.Set)), zoneid) {
+			p := cs.Snapshot.NewDeleteSnapshotParams(d.Id())
+			p.SetZoneid(z)
+
+			log.Printf("[DEBUG] Deleting snapshot %s from zone %s", d.Id(), z)
+			if _, err := cs.Snapshot.DeleteSnapshot(p); err != nil {
+				return fmt.Errorf("Error deleting snapshot %s from zone %s: %s", d.Id(), z, err)
+			}
+		}
+	}
+
+	// Check if the tags have changed and if so, update the tags
+	if d.HasChange("tags") {
+		if err := updateTags(cs, d, "Snapshot"); err != nil {
+			return fmt.Errorf("Error updating tags on snapshot %s: %s", d.Id(), err)
+		}
+	}
+
+	return resourceCloudStackSnapshotRead(d, meta)
+}
+
+func resourceCloudStackSnapshotDelete(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Create a new parameter struct
+	p := cs.Snapshot.NewDeleteSnapshotParams(d.Id())
+
+	log.Printf("[INFO] Deleting snapshot: %s", d.Id())
+	if _, err := cs.Snapshot.DeleteSnapshot(p); err != nil {
+		// This is a very poor way to be told the ID does no longer exist :(
+		if strings.Contains(err.Error(), fmt.Sprintf(
+			"Invalid parameter id value=%s due to incorrect long value format, "+
+				"or entity does not exist", d.Id())) {
+			return nil
+		}
+
+		return fmt.Errorf("Error deleting snapshot %s: %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+// snapshotCopyZoneIDs returns the zones of the set, except for the zone the
+// snapshot itself is created in
+func snapshotCopyZoneIDs(zones *schema.Set, zoneid string) []string {
+	var zoneids []string
+	for _, z := range zones.List() {
+		if z.(string) != zoneid {
+			zoneids = append(zoneids, z.(string))
+		}
+	}
+
+	return zoneids
+}
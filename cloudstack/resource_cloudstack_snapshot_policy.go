@@ -0,0 +1,223 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// The interval types of snapshot policies, in the order CloudStack numbers them
+var snapshotPolicyIntervalTypes = []string{"HOURLY", "DAILY", "WEEKLY", "MONTHLY"}
+
+func resourceCloudStackSnapshotPolicy() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceCloudStackSnapshotPolicyCreate,
+		Read:   resourceCloudStackSnapshotPolicyRead,
+		Update: resourceCloudStackSnapshotPolicyUpdate,
+		Delete: resourceCloudStackSnapshotPolicyDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: importStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"volume_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"interval_type": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateFunc:     validation.StringInSlice(snapshotPolicyIntervalTypes, true),
+				DiffSuppressFunc: suppressCaseDiff,
+			},
+
+			"schedule": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			"timezone": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			"max_snaps": {
+				Type:     schema.TypeInt,
+				Required: true,
+			},
+
+			"zone_ids": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				ForceNew: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Set:      schema.HashString,
+			},
+
+			"tags": tagsSchema(),
+		},
+	}
+}
+
+func resourceCloudStackSnapshotPolicyCreate(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	if err := createSnapshotPolicy(d, meta); err != nil {
+		return err
+	}
+
+	// Set tags if necessary
+	if err := setTags(cs, d, "SnapshotPolicy"); err != nil {
+		return fmt.Errorf("Error setting tags on snapshot policy %s: %s", d.Id(), err)
+	}
+
+	return resourceCloudStackSnapshotPolicyRead(d, meta)
+}
+
+func resourceCloudStackSnapshotPolicyRead(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Create a new parameter struct
+	p := cs.Snapshot.NewListSnapshotPoliciesParams()
+	p.SetId(d.Id())
+
+	l, err := cs.Snapshot.ListSnapshotPolicies(p)
+	if err != nil {
+		return err
+	}
+
+	if l.Count == 0 {
+		log.Printf("[DEBUG] Snapshot policy %s does no longer exist", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	s := l.SnapshotPolicies[0]
+
+	if s.Intervaltype < 0 || s.Intervaltype >= len(snapshotPolicyIntervalTypes) {
+		return fmt.Errorf("Unknown interval type %d of snapshot policy %s", s.Intervaltype, d.Id())
+	}
+
+	zoneids := &schema.Set{F: schema.HashString}
+	for _, z := range s.Zone {
+		if zone, ok := z.(map[string]interface{}); ok {
+			if id, ok := zone["id"].(string); ok {
+				zoneids.Add(id)
+			}
+		}
+	}
+
+	d.Set("volume_id", s.Volumeid)
+	d.Set("interval_type", snapshotPolicyIntervalTypes[s.Intervaltype])
+	d.Set("schedule", s.Schedule)
+	d.Set("timezone", s.Timezone)
+	d.Set("max_snaps", s.Maxsnaps)
+	d.Set("zone_ids", zoneids)
+	d.Set("tags", tagsToMap(s.Tags))
+
+	return nil
+}
+
+func resourceCloudStackSnapshotPolicyUpdate(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Creating a policy for an existing interval type of a volume updates it
+	if d.HasChanges("schedule", "timezone", "max_snaps") {
+		if err := createSnapshotPolicy(d, meta); err != nil {
+			return err
+		}
+	}
+
+	// Check if the tags have changed and if so, update the tags
+	if d.HasChange("tags") {
+		if err := updateTags(cs, d, "SnapshotPolicy"); err != nil {
+			return fmt.Errorf("Error updating tags on snapshot policy %s: %s", d.Id(), err)
+		}
+	}
+
+	return resourceCloudStackSnapshotPolicyRead(d, meta)
+}
+
+func resourceCloudStackSnapshotPolicyDelete(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Create a new parameter struct
+	p := cs.Snapshot.NewDeleteSnapshotPoliciesParams()
+	p.SetId(d.Id())
+
+	log.Printf("[INFO] Deleting snapshot policy: %s", d.Id())
+	if _, err := cs.Snapshot.DeleteSnapshotPolicies(p); err != nil {
+		// This is a very poor way to be told the ID does no longer exist :(
+		if strings.Contains(err.Error(), fmt.Sprintf(
+			"Invalid parameter id value=%s due to incorrect long value format, "+
+				"or entity does not exist", d.Id())) {
+			return nil
+		}
+
+		return fmt.Errorf("Error deleting snapshot policy %s: %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+// createSnapshotPolicy creates the snapshot policy, or updates the existing
+// policy of the volume with the same interval type
+func createSnapshotPolicy(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	volumeid := d.Get("volume_id").(string)
+	intervaltype := strings.ToUpper(d.Get("interval_type").(string))
+
+	// Create a new parameter struct
+	p := cs.Snapshot.NewCreateSnapshotPolicyParams(
+		intervaltype,
+		d.Get("max_snaps").(int),
+		d.Get("schedule").(string),
+		d.Get("timezone").(string),
+		volumeid,
+	)
+
+	if zoneids := d.Get("zone_ids").(*schema.Set); zoneids.Len() > 0 {
+		var zones []string
+		for _, z := range zoneids.List() {
+			zones = append(zones, z.(string))
+		}
+		p.SetZoneids(zones)
+	}
+
+	log.Printf("[DEBUG] Creating %s snapshot policy for volume %s", intervaltype, volumeid)
+	r, err := cs.Snapshot.CreateSnapshotPolicy(p)
+	if err != nil {
+		return fmt.Errorf("Error creating %s snapshot policy for volume %s: %s", intervaltype, volumeid, err)
+	}
+
+	d.SetId(r.Id)
+
+	return nil
+}
@@ -0,0 +1,158 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccCloudStackSnapshotPolicy_basic(t *testing.T) {
+	var policy cloudstack.SnapshotPolicy
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackSnapshotPolicyDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackSnapshotPolicy_basic("30:2", 7),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackSnapshotPolicyExists(
+						"cloudstack_snapshot_policy.foo", &policy),
+					resource.TestCheckResourceAttr(
+						"cloudstack_snapshot_policy.foo", "interval_type", "DAILY"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_snapshot_policy.foo", "schedule", "30:2"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_snapshot_policy.foo", "max_snaps", "7"),
+				),
+			},
+
+			{
+				Config: testAccCloudStackSnapshotPolicy_basic("0:4", 14),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackSnapshotPolicyExists(
+						"cloudstack_snapshot_policy.foo", &policy),
+					resource.TestCheckResourceAttr(
+						"cloudstack_snapshot_policy.foo", "schedule", "0:4"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_snapshot_policy.foo", "max_snaps", "14"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudStackSnapshotPolicy_import(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackSnapshotPolicyDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackSnapshotPolicy_basic("30:2", 7),
+			},
+
+			{
+				ResourceName:      "cloudstack_snapshot_policy.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckCloudStackSnapshotPolicyExists(
+	n string, policy *cloudstack.SnapshotPolicy) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No snapshot policy ID is set")
+		}
+
+		cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+		p := cs.Snapshot.NewListSnapshotPoliciesParams()
+		p.SetId(rs.Primary.ID)
+
+		l, err := cs.Snapshot.ListSnapshotPolicies(p)
+		if err != nil {
+			return err
+		}
+
+		if l.Count != 1 || l.SnapshotPolicies[0].Id != rs.Primary.ID {
+			return fmt.Errorf("Snapshot policy not found")
+		}
+
+		*policy = *l.SnapshotPolicies[0]
+
+		return nil
+	}
+}
+
+func testAccCheckCloudStackSnapshotPolicyDestroy(s *terraform.State) error {
+	cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "cloudstack_snapshot_policy" {
+			continue
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No snapshot policy ID is set")
+		}
+
+		p := cs.Snapshot.NewListSnapshotPoliciesParams()
+		p.SetId(rs.Primary.ID)
+
+		l, err := cs.Snapshot.ListSnapshotPolicies(p)
+		if err == nil && l.Count > 0 {
+			return fmt.Errorf("Snapshot policy %s still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+func testAccCloudStackSnapshotPolicy_basic(schedule string, maxSnaps int) string {
+	return fmt.Sprintf(`
+resource "cloudstack_disk" "foo" {
+  name = "terraform-disk"
+  attach = false
+  disk_offering = "Small"
+  zone = "Sandbox-simulator"
+}
+
+resource "cloudstack_snapshot_policy" "foo" {
+  volume_id = cloudstack_disk.foo.id
+  interval_type = "DAILY"
+  schedule = "%s"
+  timezone = "Europe/Amsterdam"
+  max_snaps = %d
+}`, schedule, maxSnaps)
+}
@@ -0,0 +1,158 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccCloudStackSnapshot_basic(t *testing.T) {
+	var snapshot cloudstack.Snapshot
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackSnapshotDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackSnapshot_basic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackSnapshotExists(
+						"cloudstack_snapshot.foo", &snapshot),
+					testAccCheckCloudStackSnapshotAttributes(&snapshot),
+					resource.TestCheckResourceAttr(
+						"cloudstack_snapshot.foo", "tags.terraform-tag", "true"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudStackSnapshot_import(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackSnapshotDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackSnapshot_basic,
+			},
+
+			{
+				ResourceName:            "cloudstack_snapshot.foo",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"quiescevm"},
+			},
+		},
+	})
+}
+
+func testAccCheckCloudStackSnapshotExists(
+	n string, snapshot *cloudstack.Snapshot) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No snapshot ID is set")
+		}
+
+		cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+		p := cs.Snapshot.NewListSnapshotsParams()
+		p.SetId(rs.Primary.ID)
+
+		l, err := cs.Snapshot.ListSnapshots(p)
+		if err != nil {
+			return err
+		}
+
+		if l.Count != 1 || l.Snapshots[0].Id != rs.Primary.ID {
+			return fmt.Errorf("Snapshot not found")
+		}
+
+		*snapshot = *l.Snapshots[0]
+
+		return nil
+	}
+}
+
+func testAccCheckCloudStackSnapshotAttributes(
+	snapshot *cloudstack.Snapshot) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+
+		if snapshot.Name != "terraform-snapshot" {
+			return fmt.Errorf("Bad name: %s", snapshot.Name)
+		}
+
+		if snapshot.Volumename != "terraform-disk" {
+			return fmt.Errorf("Bad volume: %s", snapshot.Volumename)
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckCloudStackSnapshotDestroy(s *terraform.State) error {
+	cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "cloudstack_snapshot" {
+			continue
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No snapshot ID is set")
+		}
+
+		p := cs.Snapshot.NewListSnapshotsParams()
+		p.SetId(rs.Primary.ID)
+
+		l, err := cs.Snapshot.ListSnapshots(p)
+		if err == nil && l.Count > 0 {
+			return fmt.Errorf("Snapshot %s still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+const testAccCloudStackSnapshot_basic = `
+resource "cloudstack_disk" "foo" {
+  name = "terraform-disk"
+  attach = false
+  disk_offering = "Small"
+  zone = "Sandbox-simulator"
+}
+
+resource "cloudstack_snapshot" "foo" {
+  volume_id = cloudstack_disk.foo.id
+  name = "terraform-snapshot"
+  tags = {
+    terraform-tag = "true"
+  }
+}`
@@ -0,0 +1,457 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// storagePoolMaintenanceTimeout is the maximum time in seconds to wait until a
+// storage pool is in maintenance mode
+const storagePoolMaintenanceTimeout = 600
+
+func resourceCloudStackStoragePool() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceCloudStackStoragePoolCreate,
+		Read:   resourceCloudStackStoragePoolRead,
+		Update: resourceCloudStackStoragePoolUpdate,
+		Delete: resourceCloudStackStoragePoolDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: importStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			"url": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressImportedStoragePoolDiff,
+			},
+
+			"zone": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"scope": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ForceNew:         true,
+				ValidateFunc:     validation.StringInSlice([]string{"ZONE", "CLUSTER", "HOST"}, true),
+				DiffSuppressFunc: suppressCaseDiff,
+			},
+
+			"pod_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"cluster_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"hypervisor": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"storage_provider": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"managed": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"tags": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Set:      schema.HashString,
+			},
+
+			"is_tag_a_rule": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+
+			"capacity_bytes": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+			},
+
+			"capacity_iops": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+			},
+
+			"details": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+
+			"enabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+
+			"maintenance": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+
+			"forced": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+
+			"type": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"state": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceCloudStackStoragePoolCreate(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	name := d.Get("name").(string)
+
+	// Retrieve the zone ID
+	zoneid, e := retrieveID(cs, "zone", d.Get("zone").(string))
+	if e != nil {
+		return e.Error()
+	}
+
+	// Create a new parameter struct
+	p := cs.Pool.NewCreateStoragePoolParams(name, d.Get("url").(string), zoneid)
+
+	if scope, ok := d.GetOk("scope"); ok {
+		p.SetScope(strings.ToLower(scope.(string)))
+	}
+
+	if podid, ok := d.GetOk("pod_id"); ok {
+		p.SetPodid(podid.(string))
+	}
+
+	if clusterid, ok := d.GetOk("cluster_id"); ok {
+		p.SetClusterid(clusterid.(string))
+	}
+
+	if hypervisor, ok := d.GetOk("hypervisor"); ok {
+		p.SetHypervisor(hypervisor.(string))
+	}
+
+	if provider, ok := d.GetOk("storage_provider"); ok {
+		p.SetProvider(provider.(string))
+	}
+
+	if managed, ok := d.GetOk("managed"); ok {
+		p.SetManaged(managed.(bool))
+	}
+
+	if tags := d.Get("tags").(*schema.Set); tags.Len() > 0 {
+		p.SetTags(strings.Join(storagePoolTags(tags), ","))
+		p.SetIstagarule(d.Get("is_tag_a_rule").(bool))
+	}
+
+	if capacitybytes, ok := d.GetOk("capacity_bytes"); ok {
+		p.SetCapacitybytes(int64(capacitybytes.(int)))
+	}
+
+	if capacityiops, ok := d.GetOk("capacity_iops"); ok {
+		p.SetCapacityiops(int64(capacityiops.(int)))
+	}
+
+	if details, ok := d.GetOk("details"); ok {
+		p.SetDetails(stringMapFromSchema(details.(map[string]interface{})))
+	}
+
+	log.Printf("[DEBUG] Creating storage pool %s", name)
+	r, err := cs.Pool.CreateStoragePool(p)
+	if err != nil {
+		return fmt.Errorf("Error creating storage pool %s: %s", name, err)
+	}
+
+	d.SetId(r.Id)
+
+	if !d.Get("enabled").(bool) {
+		u := cs.Pool.NewUpdateStoragePoolParams(d.Id())
+		u.SetEnabled(false)
+
+		if _, err := cs.Pool.UpdateStoragePool(u); err != nil {
+			return fmt.Errorf("Error disabling storage pool %s: %s", name, err)
+		}
+	}
+
+	if d.Get("maintenance").(bool) {
+		if err := setStoragePoolMaintenance(cs, d.Id(), true); err != nil {
+			return err
+		}
+	}
+
+	return resourceCloudStackStoragePoolRead(d, meta)
+}
+
+func resourceCloudStackStoragePoolRead(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Get the storage pool details
+	s, count, err := cs.Pool.GetStoragePoolByID(d.Id())
+	if err != nil {
+		if count == 0 {
+			log.Printf("[DEBUG] Storage pool %s does no longer exist", d.Get("name").(string))
+			d.SetId("")
+			return nil
+		}
+
+		return err
+	}
+
+	d.Set("name", s.Name)
+	d.Set("scope", s.Scope)
+	d.Set("pod_id", s.Podid)
+	d.Set("cluster_id", s.Clusterid)
+	d.Set("hypervisor", s.Hypervisor)
+	d.Set("storage_provider", s.Provider)
+	d.Set("managed", s.Managed)
+	d.Set("is_tag_a_rule", s.Istagarule)
+	d.Set("capacity_bytes", int(s.Disksizetotal))
+	d.Set("capacity_iops", int(s.Capacityiops))
+	d.Set("enabled", s.State != "Disabled")
+	d.Set("maintenance", storagePoolInMaintenance(s.State))
+	d.Set("type", s.Type)
+	d.Set("state", s.State)
+
+	tags := &schema.Set{F: schema.HashString}
+	if s.Tags != "" {
+		for _, t := range strings.Split(s.Tags, ",") {
+			tags.Add(strings.TrimSpace(t))
+		}
+	}
+	d.Set("tags", tags)
+
+	setValueOrID(d, "zone", s.Zonename, s.Zoneid)
+
+	return nil
+}
+
+func resourceCloudStackStoragePoolUpdate(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	name := d.Get("name").(string)
+
+	// A pool has to leave maintenance before it can be updated
+	if d.HasChange("maintenance") && !d.Get("maintenance").(bool) {
+		if err := setStoragePoolMaintenance(cs, d.Id(), false); err != nil {
+			return err
+		}
+	}
+
+	if d.HasChanges("name", "tags", "is_tag_a_rule", "capacity_bytes", "capacity_iops", "details", "enabled") {
+		// Create a new parameter struct
+		p := cs.Pool.NewUpdateStoragePoolParams(d.Id())
+
+		if d.HasChange("name") {
+			p.SetName(name)
+		}
+
+		if d.HasChanges("tags", "is_tag_a_rule") {
+			p.SetTags(storagePoolTags(d.Get("tags").(*schema.Set)))
+			p.SetIstagarule(d.Get("is_tag_a_rule").(bool))
+		}
+
+		if d.HasChange("capacity_bytes") {
+			p.SetCapacitybytes(int64(d.Get("capacity_bytes").(int)))
+		}
+
+		if d.HasChange("capacity_iops") {
+			p.SetCapacityiops(int64(d.Get("capacity_iops").(int)))
+		}
+
+		if d.HasChange("details") {
+			p.SetDetails(stringMapFromSchema(d.Get("details").(map[string]interface{})))
+		}
+
+		if d.HasChange("enabled") {
+			p.SetEnabled(d.Get("enabled").(bool))
+		}
+
+		log.Printf("[DEBUG] Updating storage pool %s", name)
+		if _, err := cs.Pool.UpdateStoragePool(p); err != nil {
+			return fmt.Errorf("Error updating storage pool %s: %s", name, err)
+		}
+	}
+
+	if d.HasChange("maintenance") && d.Get("maintenance").(bool) {
+		if err := setStoragePoolMaintenance(cs, d.Id(), true); err != nil {
+			return err
+		}
+	}
+
+	return resourceCloudStackStoragePoolRead(d, meta)
+}
+
+func resourceCloudStackStoragePoolDelete(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// A storage pool can only be deleted when it is in maintenance mode
+	if err := enterStoragePoolMaintenance(cs, d.Id()); err != nil {
+		return err
+	}
+
+	// Create a new parameter struct
+	p := cs.Pool.NewDeleteStoragePoolParams(d.Id())
+	p.SetForced(d.Get("forced").(bool))
+
+	log.Printf("[INFO] Deleting storage pool: %s", d.Get("name").(string))
+	if _, err := cs.Pool.DeleteStoragePool(p); err != nil {
+		// This is a very poor way to be told the ID does no longer exist :(
+		if strings.Contains(err.Error(), fmt.Sprintf(
+			"Invalid parameter id value=%s due to incorrect long value format, "+
+				"or entity does not exist", d.Id())) {
+			return nil
+		}
+
+		return fmt.Errorf("Error deleting storage pool %s: %s", d.Get("name").(string), err)
+	}
+
+	return nil
+}
+
+// setStoragePoolMaintenance puts a storage pool into maintenance mode, or
+// cancels the maintenance mode of the pool
+func setStoragePoolMaintenance(cs *cloudstack.CloudStackClient, id string, maintenance bool) error {
+	if maintenance {
+		log.Printf("[DEBUG] Enabling maintenance mode of storage pool %s", id)
+		p := cs.StoragePool.NewEnableStorageMaintenanceParams(id)
+		if _, err := cs.StoragePool.EnableStorageMaintenance(p); err != nil {
+			return fmt.Errorf("Error enabling maintenance mode of storage pool %s: %s", id, err)
+		}
+
+		return nil
+	}
+
+	log.Printf("[DEBUG] Cancelling maintenance mode of storage pool %s", id)
+	p := cs.StoragePool.NewCancelStorageMaintenanceParams(id)
+	if _, err := cs.StoragePool.CancelStorageMaintenance(p); err != nil {
+		return fmt.Errorf("Error cancelling maintenance mode of storage pool %s: %s", id, err)
+	}
+
+	return nil
+}
+
+// enterStoragePoolMaintenance puts a storage pool into maintenance mode based
+// on its current state, and waits until a pending maintenance is finished
+func enterStoragePoolMaintenance(cs *cloudstack.CloudStackClient, id string) error {
+	s, count, err := cs.Pool.GetStoragePoolByID(id)
+	if err != nil {
+		if count == 0 {
+			return nil
+		}
+
+		return err
+	}
+
+	switch s.State {
+	case "Maintenance", "ErrorInMaintenance":
+		return nil
+	case "PrepareForMaintenance":
+		// Wait until the pending maintenance is finished
+	default:
+		if err := setStoragePoolMaintenance(cs, id, true); err != nil {
+			return err
+		}
+	}
+
+	return waitForReady(storagePoolMaintenanceTimeout, "storage pool maintenance", func() (bool, error) {
+		s, _, err := cs.Pool.GetStoragePoolByID(id)
+		if err != nil {
+			return false, err
+		}
+
+		return s.State != "PrepareForMaintenance", nil
+	})
+}
+
+// suppressImportedStoragePoolDiff suppresses the diff of arguments that are
+// not returned by the API, so an imported storage pool is not replaced
+func suppressImportedStoragePoolDiff(k, old, new string, d *schema.ResourceData) bool {
+	return d.Id() != "" && old == ""
+}
+
+// storagePoolInMaintenance reports whether the state of a storage pool is one
+// of the (transitional) maintenance states
+func storagePoolInMaintenance(state string) bool {
+	switch state {
+	case "PrepareForMaintenance", "Maintenance", "ErrorInMaintenance":
+		return true
+	}
+
+	return false
+}
+
+func storagePoolTags(tags *schema.Set) []string {
+	var t []string
+	for _, tag := range tags.List() {
+		t = append(t, tag.(string))
+	}
+
+	return t
+}
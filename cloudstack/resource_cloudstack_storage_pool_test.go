@@ -0,0 +1,168 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccCloudStackStoragePool_basic(t *testing.T) {
+	var pool cloudstack.StoragePool
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackStoragePoolDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackStoragePool_basic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackStoragePoolExists(
+						"cloudstack_storage_pool.foo", &pool),
+					resource.TestCheckResourceAttr(
+						"cloudstack_storage_pool.foo", "name", "terraform-pool"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_storage_pool.foo", "scope", "ZONE"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_storage_pool.foo", "tags.#", "1"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_storage_pool.foo", "maintenance", "false"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_storage_pool.foo", "state", "Up"),
+				),
+			},
+
+			{
+				Config: testAccCloudStackStoragePool_update,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackStoragePoolExists(
+						"cloudstack_storage_pool.foo", &pool),
+					resource.TestCheckResourceAttr(
+						"cloudstack_storage_pool.foo", "name", "terraform-pool-updated"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_storage_pool.foo", "tags.#", "2"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_storage_pool.foo", "maintenance", "true"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_storage_pool.foo", "state", "Maintenance"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudStackStoragePool_import(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackStoragePoolDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackStoragePool_basic,
+			},
+
+			{
+				ResourceName:            "cloudstack_storage_pool.foo",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"url", "details", "forced"},
+			},
+		},
+	})
+}
+
+func testAccCheckCloudStackStoragePoolExists(
+	n string, pool *cloudstack.StoragePool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No storage pool ID is set")
+		}
+
+		cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+		p, _, err := cs.Pool.GetStoragePoolByID(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		if p.Id != rs.Primary.ID {
+			return fmt.Errorf("Storage pool not found")
+		}
+
+		*pool = *p
+
+		return nil
+	}
+}
+
+func testAccCheckCloudStackStoragePoolDestroy(s *terraform.State) error {
+	cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "cloudstack_storage_pool" {
+			continue
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No storage pool ID is set")
+		}
+
+		_, _, err := cs.Pool.GetStoragePoolByID(rs.Primary.ID)
+		if err == nil {
+			return fmt.Errorf("Storage pool %s still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+const testAccCloudStackStoragePool_basic = `
+resource "cloudstack_storage_pool" "foo" {
+  name = "terraform-pool"
+  url = "nfs://10.147.28.6/export/home/sandbox/terraform"
+  zone = "Sandbox-simulator"
+  scope = "zone"
+  hypervisor = "Simulator"
+  tags = ["terraform"]
+  capacity_bytes = 10737418240
+  forced = true
+}`
+
+const testAccCloudStackStoragePool_update = `
+resource "cloudstack_storage_pool" "foo" {
+  name = "terraform-pool-updated"
+  url = "nfs://10.147.28.6/export/home/sandbox/terraform"
+  zone = "Sandbox-simulator"
+  scope = "zone"
+  hypervisor = "Simulator"
+  tags = ["terraform", "ssd"]
+  capacity_bytes = 10737418240
+  maintenance = true
+  forced = true
+}`
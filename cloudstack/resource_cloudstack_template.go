@@ -20,10 +20,10 @@
 package cloudstack
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"strings"
-	"time"
 
 	"github.com/apache/cloudstack-go/v2/cloudstack"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -36,6 +36,8 @@ func resourceCloudStackTemplate() *schema.Resource {
 		Update: resourceCloudStackTemplateUpdate,
 		Delete: resourceCloudStackTemplateDelete,
 
+		CustomizeDiff: resourceCloudStackTemplateCustomizeDiff,
+
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:     schema.TypeString,
@@ -50,12 +52,14 @@ func resourceCloudStackTemplate() *schema.Resource {
 
 			"format": {
 				Type:     schema.TypeString,
-				Required: true,
+				Optional: true,
+				Computed: true,
 			},
 
 			"hypervisor": {
 				Type:     schema.TypeString,
-				Required: true,
+				Optional: true,
+				Computed: true,
 				ForceNew: true,
 			},
 
@@ -65,8 +69,21 @@ func resourceCloudStackTemplate() *schema.Resource {
 			},
 
 			"url": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{"url", "source_volume_id", "source_snapshot_id"},
+			},
+
+			"source_volume_id": {
 				Type:     schema.TypeString,
-				Required: true,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"source_snapshot_id": {
+				Type:     schema.TypeString,
+				Optional: true,
 				ForceNew: true,
 			},
 
@@ -80,9 +97,17 @@ func resourceCloudStackTemplate() *schema.Resource {
 			"zone": {
 				Type:     schema.TypeString,
 				Optional: true,
+				Computed: true,
 				ForceNew: true,
 			},
 
+			"zones": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Set:      schema.HashString,
+			},
+
 			"is_dynamically_scalable": {
 				Type:     schema.TypeBool,
 				Optional: true,
@@ -126,6 +151,12 @@ func resourceCloudStackTemplate() *schema.Resource {
 				Default:  300,
 			},
 
+			"zone_ready": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeBool},
+			},
+
 			"tags": tagsSchema(),
 		},
 	}
@@ -140,6 +171,47 @@ func resourceCloudStackTemplateCreate(d *schema.ResourceData, meta interface{})
 
 	name := d.Get("name").(string)
 
+	var id string
+	var err error
+
+	// Register the template when it's downloaded from a URL, or create it
+	// from a volume or snapshot otherwise
+	if _, ok := d.GetOk("url"); ok {
+		id, err = registerTemplate(cs, d)
+	} else {
+		id, err = createTemplate(cs, d)
+	}
+	if err != nil {
+		return fmt.Errorf("Error creating template %s: %s", name, err)
+	}
+
+	d.SetId(id)
+
+	// Set tags if necessary
+	if err = setTags(cs, d, "Template"); err != nil {
+		return fmt.Errorf("Error setting tags on the template %s: %s", name, err)
+	}
+
+	// Wait until the template is ready to use, or timeout with an error...
+	err = waitForReady(int64(d.Get("is_ready_timeout").(int)), "template", func() (bool, error) {
+		if err := resourceCloudStackTemplateRead(d, meta); err != nil {
+			return false, err
+		}
+
+		return d.Get("is_ready").(bool), nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Copy the template to any additional zones
+	return copyTemplateToZones(d, meta, d.Get("zones").(*schema.Set))
+}
+
+// registerTemplate registers a template that is downloaded from a URL
+func registerTemplate(cs *cloudstack.CloudStackClient, d *schema.ResourceData) (string, error) {
+	name := d.Get("name").(string)
+
 	// Compute/set the display text
 	displaytext := d.Get("display_text").(string)
 	if displaytext == "" {
@@ -187,7 +259,7 @@ func resourceCloudStackTemplateCreate(d *schema.ResourceData, meta interface{})
 		if v.(string) != "all" {
 			zoneid, e := retrieveID(cs, "zone", v.(string))
 			if e != nil {
-				return e.Error()
+				return "", e.Error()
 			}
 			p.SetZoneid(zoneid)
 		} else {
@@ -197,43 +269,94 @@ func resourceCloudStackTemplateCreate(d *schema.ResourceData, meta interface{})
 
 	// If there is a project supplied, we retrieve and set the project id
 	if err := setProjectid(p, cs, d); err != nil {
-		return err
+		return "", err
 	}
 
-	// Create the new template
+	// Register the new template
 	r, err := cs.Template.RegisterTemplate(p)
 	if err != nil {
-		return fmt.Errorf("Error creating template %s: %s", name, err)
+		return "", err
 	}
 
-	d.SetId(r.RegisterTemplate[0].Id)
+	return r.RegisterTemplate[0].Id, nil
+}
 
-	// Set tags if necessary
-	if err = setTags(cs, d, "Template"); err != nil {
-		return fmt.Errorf("Error setting tags on the template %s: %s", name, err)
+// createTemplate creates a template from an existing volume or snapshot
+func createTemplate(cs *cloudstack.CloudStackClient, d *schema.ResourceData) (string, error) {
+	name := d.Get("name").(string)
+
+	// Compute/set the display text
+	displaytext := d.Get("display_text").(string)
+	if displaytext == "" {
+		displaytext = name
 	}
 
-	// Wait until the template is ready to use, or timeout with an error...
-	currentTime := time.Now().Unix()
-	timeout := int64(d.Get("is_ready_timeout").(int))
-	for {
-		// Start with the sleep so the register action has a few seconds
-		// to process the registration correctly. Without this wait
-		time.Sleep(10 * time.Second)
-
-		err := resourceCloudStackTemplateRead(d, meta)
-		if err != nil {
-			return err
-		}
+	// Retrieve the os_type ID
+	ostypeid, e := retrieveID(cs, "os_type", d.Get("os_type").(string))
+	if e != nil {
+		return "", e.Error()
+	}
 
-		if d.Get("is_ready").(bool) {
-			return nil
+	// Create a new parameter struct
+	p := cs.Template.NewCreateTemplateParams(displaytext, name, ostypeid)
+
+	if v, ok := d.GetOk("source_volume_id"); ok {
+		p.SetVolumeid(v.(string))
+	}
+
+	if v, ok := d.GetOk("source_snapshot_id"); ok {
+		p.SetSnapshotid(v.(string))
+	}
+
+	// Set optional parameters
+	if v, ok := d.GetOk("is_dynamically_scalable"); ok {
+		p.SetIsdynamicallyscalable(v.(bool))
+	}
+
+	if v, ok := d.GetOk("is_featured"); ok {
+		p.SetIsfeatured(v.(bool))
+	}
+
+	if v, ok := d.GetOk("is_public"); ok {
+		p.SetIspublic(v.(bool))
+	}
+
+	if v, ok := d.GetOk("password_enabled"); ok {
+		p.SetPasswordenabled(v.(bool))
+	}
+
+	// Retrieve the zone ID
+	if v, ok := d.GetOk("zone"); ok {
+		zoneid, e := retrieveID(cs, "zone", v.(string))
+		if e != nil {
+			return "", e.Error()
 		}
+		p.SetZoneid(zoneid)
+	}
 
-		if time.Now().Unix()-currentTime > timeout {
-			return fmt.Errorf("Timeout while waiting for template to become ready")
+	// If there is a project supplied, we retrieve and set the project id
+	if err := setProjectid(p, cs, d); err != nil {
+		return "", err
+	}
+
+	// Create the new template
+	r, err := cs.Template.CreateTemplate(p)
+	if err != nil {
+		return "", err
+	}
+
+	// The extractable flag cannot be passed when creating a template, so it
+	// is set afterwards when configured (also when it is set to false)
+	if v := d.GetRawConfig().GetAttr("is_extractable"); !v.IsNull() {
+		pp := cs.Template.NewUpdateTemplatePermissionsParams(r.Id)
+		pp.SetIsextractable(v.True())
+
+		if _, err := cs.Template.UpdateTemplatePermissions(pp); err != nil {
+			return "", fmt.Errorf("Error setting the extractable flag: %s", err)
 		}
 	}
+
+	return r.Id, nil
 }
 
 func resourceCloudStackTemplateRead(d *schema.ResourceData, meta interface{}) error {
@@ -242,7 +365,9 @@ func resourceCloudStackTemplateRead(d *schema.ResourceData, meta interface{}) er
 	// Get the template details
 	p := cs.Template.NewListTemplatesParams("executable")
 	p.SetId(d.Id())
-	p.SetShowunique(true)
+
+	// List a template per zone it is available in
+	p.SetShowunique(false)
 	project := d.Get("project").(string)
 	if project != "" {
 		if !cloudstack.IsID(project) {
@@ -265,7 +390,32 @@ func resourceCloudStackTemplateRead(d *schema.ResourceData, meta interface{}) er
 		return nil
 	}
 
+	// Use the template of the zone it is registered in for the attributes
+	zone := d.Get("zone").(string)
 	t := r.Templates[0]
+	for _, template := range r.Templates {
+		if template.Zoneid == zone || template.Zonename == zone {
+			t = template
+		}
+	}
+
+	// Report the copies in other zones by the name or ID they are configured with
+	configured := d.Get("zones").(*schema.Set)
+	zones := &schema.Set{F: schema.HashString}
+	zoneReady := make(map[string]interface{})
+	for _, template := range r.Templates {
+		zoneReady[template.Zoneid] = template.Isready
+
+		if t.CrossZones || template.Zoneid == t.Zoneid {
+			continue
+		}
+
+		if configured.Contains(template.Zonename) {
+			zones.Add(template.Zonename)
+		} else {
+			zones.Add(template.Zoneid)
+		}
+	}
 
 	d.Set("name", t.Name)
 	d.Set("display_text", t.Displaytext)
@@ -277,6 +427,8 @@ func resourceCloudStackTemplateRead(d *schema.ResourceData, meta interface{}) er
 	d.Set("is_public", t.Ispublic)
 	d.Set("password_enabled", t.Passwordenabled)
 	d.Set("is_ready", t.Isready)
+	d.Set("zones", zones)
+	d.Set("zone_ready", zoneReady)
 
 	tags := make(map[string]interface{})
 	for _, tag := range t.Tags {
@@ -331,6 +483,31 @@ func resourceCloudStackTemplateUpdate(d *schema.ResourceData, meta interface{})
 		return fmt.Errorf("Error updating template %s: %s", name, err)
 	}
 
+	// Check if the zones have changed and if so, delete the copies from the
+	// zones that are removed and copy the template to the new zones
+	if d.HasChange("zones") {
+		o, n := d.GetChange("zones")
+
+		for _, z := range o.(*schema.Set).Difference(n.(*schema.Set)).List() {
+			zoneid, e := retrieveID(cs, "zone", z.(string))
+			if e != nil {
+				return e.Error()
+			}
+
+			p := cs.Template.NewDeleteTemplateParams(d.Id())
+			p.SetZoneid(zoneid)
+
+			log.Printf("[DEBUG] Deleting template %s from zone %s", name, z.(string))
+			if _, err := cs.Template.DeleteTemplate(p); err != nil {
+				return fmt.Errorf("Error deleting template %s from zone %s: %s", name, z.(string), err)
+			}
+		}
+
+		if err := copyTemplateToZones(d, meta, n.(*schema.Set).Difference(o.(*schema.Set))); err != nil {
+			return err
+		}
+	}
+
 	if d.HasChange("tags") {
 		if err := updateTags(cs, d, "Template"); err != nil {
 			return fmt.Errorf("Error updating tags on template %s: %s", name, err)
@@ -362,7 +539,124 @@ func resourceCloudStackTemplateDelete(d *schema.ResourceData, meta interface{})
 	return nil
 }
 
+func resourceCloudStackTemplateCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	zones := d.Get("zones").(*schema.Set)
+	if zones.Len() == 0 || !d.NewValueKnown("zone") || !d.NewValueKnown("zones") {
+		return nil
+	}
+
+	zone := d.Get("zone").(string)
+	if zone == "" {
+		return nil
+	}
+
+	// A template registered in all zones is already available everywhere
+	if zone == "all" {
+		return fmt.Errorf("zones cannot be used with a template registered in all zones")
+	}
+
+	// The zone of the template itself is not a copy, so it cannot be listed
+	// in zones as well (this would result in a perpetual diff)
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	zoneid, e := retrieveID(cs, "zone", zone)
+	if e != nil {
+		return e.Error()
+	}
+
+	for _, z := range zones.List() {
+		id, e := retrieveID(cs, "zone", z.(string))
+		if e != nil {
+			return e.Error()
+		}
+
+		if id == zoneid {
+			return fmt.Errorf("zones cannot contain the zone of the template itself (%s)", z.(string))
+		}
+	}
+
+	return nil
+}
+
+// copyTemplateToZones copies a template to the given zones, except for the zone
+// it is registered in, and waits until the copies are ready to use
+func copyTemplateToZones(d *schema.ResourceData, meta interface{}, zones *schema.Set) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	if zones.Len() == 0 {
+		return nil
+	}
+
+	name := d.Get("name").(string)
+
+	if d.Get("zone").(string) == "all" {
+		return fmt.Errorf("Template %s is available in all zones and cannot be copied", name)
+	}
+
+	// Retrieve the zone ID of the source template
+	sourcezoneid, e := retrieveID(cs, "zone", d.Get("zone").(string))
+	if e != nil {
+		return e.Error()
+	}
+
+	var zoneids []string
+	for _, z := range zones.List() {
+		zoneid, e := retrieveID(cs, "zone", z.(string))
+		if e != nil {
+			return e.Error()
+		}
+
+		if zoneid != sourcezoneid {
+			zoneids = append(zoneids, zoneid)
+		}
+	}
+
+	if len(zoneids) == 0 {
+		return nil
+	}
+
+	// Create a new parameter struct
+	p := cs.Template.NewCopyTemplateParams(d.Id())
+	p.SetSourcezoneid(sourcezoneid)
+	p.SetDestzoneids(zoneids)
+
+	log.Printf("[DEBUG] Copying template %s to zones %v", name, zoneids)
+	if _, err := cs.Template.CopyTemplate(p); err != nil {
+		return fmt.Errorf("Error copying template %s: %s", name, err)
+	}
+
+	// Wait until the copies are ready to use, or timeout with an error...
+	for _, zoneid := range zoneids {
+		err := waitForReady(int64(d.Get("is_ready_timeout").(int)), "template in zone "+zoneid, func() (bool, error) {
+			if err := resourceCloudStackTemplateRead(d, meta); err != nil {
+				return false, err
+			}
+
+			ready, _ := d.Get("zone_ready").(map[string]interface{})[zoneid].(bool)
+			return ready, nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func verifyTemplateParams(d *schema.ResourceData) error {
+	// Volumes and snapshots already have a format and hypervisor
+	if _, ok := d.GetOk("url"); !ok {
+		if d.Get("zone").(string) == "all" {
+			return fmt.Errorf("A template created from a volume or snapshot cannot be created in all zones")
+		}
+
+		return nil
+	}
+
+	if _, ok := d.GetOk("hypervisor"); !ok {
+		return fmt.Errorf("A hypervisor is required when registering a template from a URL")
+	}
+
 	format := d.Get("format").(string)
 	if format != "OVA" && format != "QCOW2" && format != "RAW" && format != "VHD" && format != "VMDK" {
 		return fmt.Errorf(
@@ -88,6 +88,80 @@ func TestAccCloudStackTemplate_update(t *testing.T) {
 	})
 }
 
+func TestAccCloudStackTemplate_fromSnapshot(t *testing.T) {
+	var template cloudstack.Template
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackTemplateDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackTemplate_fromSnapshot,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackTemplateExists(
+						"cloudstack_template.foo", &template),
+					resource.TestCheckResourceAttr(
+						"cloudstack_template.foo", "name", "terraform-snapshot"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_template.foo", "os_type", "CentOS 5.6 (64-bit)"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_template.foo", "is_public", "false"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_template.foo", "is_ready", "true"),
+					resource.TestCheckResourceAttrSet(
+						"cloudstack_template.foo", "format"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudStackTemplate_zones(t *testing.T) {
+	if cloudStackTemplateURL == "" {
+		t.Skip("This test requires an upload URL")
+	}
+
+	if cloudStackSecondZone == "" {
+		t.Skip("This test requires a second zone")
+	}
+
+	var template cloudstack.Template
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackTemplateDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackTemplate_zones,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackTemplateExists(
+						"cloudstack_template.foo", &template),
+					resource.TestCheckResourceAttr(
+						"cloudstack_template.foo", "zones.#", "1"),
+					resource.TestCheckTypeSetElemAttr(
+						"cloudstack_template.foo", "zones.*", cloudStackSecondZone),
+					resource.TestCheckResourceAttr(
+						"cloudstack_template.foo", "zone_ready.%", "2"),
+				),
+			},
+
+			{
+				Config: testAccCloudStackTemplate_basic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackTemplateExists(
+						"cloudstack_template.foo", &template),
+					resource.TestCheckResourceAttr(
+						"cloudstack_template.foo", "zones.#", "0"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_template.foo", "zone_ready.%", "1"),
+				),
+			},
+		},
+	})
+}
+
 func testAccCheckCloudStackTemplateExists(
 	n string, template *cloudstack.Template) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
@@ -101,7 +175,8 @@ func testAccCheckCloudStackTemplateExists(
 		}
 
 		cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
-		tmpl, _, err := cs.Template.GetTemplateByID(rs.Primary.ID, "executable")
+		tmpl, _, err := cs.Template.GetTemplateByID(
+			rs.Primary.ID, "executable", cloudstack.WithZone(rs.Primary.Attributes["zone"]))
 
 		if err != nil {
 			return err
@@ -211,3 +286,59 @@ resource "cloudstack_template" "foo" {
   password_enabled = true
   zone = "Sandbox-simulator"
 }`, cloudStackTemplateURL)
+
+const testAccCloudStackTemplate_fromSnapshot = `
+resource "cloudstack_network" "foo" {
+  name = "terraform-network"
+  display_text = "terraform-network"
+  cidr = "10.1.1.0/24"
+  network_offering = "DefaultIsolatedNetworkOfferingWithSourceNatService"
+  zone = "Sandbox-simulator"
+}
+
+resource "cloudstack_instance" "foobar" {
+  name = "terraform-test"
+  display_name = "terraform-test"
+  service_offering= "Small Instance"
+  network_id = cloudstack_network.foo.id
+  template = "CentOS 5.6 (64-bit) no GUI (Simulator)"
+  zone = "Sandbox-simulator"
+  expunge = true
+}
+
+data "cloudstack_volume" "root" {
+  filter {
+    name = "virtual_machine_id"
+    value = cloudstack_instance.foobar.id
+  }
+  filter {
+    name = "type"
+    value = "ROOT"
+  }
+}
+
+resource "cloudstack_snapshot" "foo" {
+  volume_id = data.cloudstack_volume.root.id
+  name = "terraform-snapshot"
+}
+
+resource "cloudstack_template" "foo" {
+  name = "terraform-snapshot"
+  os_type = "CentOS 5.6 (64-bit)"
+  source_snapshot_id = cloudstack_snapshot.foo.id
+  is_public = false
+}`
+
+var testAccCloudStackTemplate_zones = fmt.Sprintf(`
+resource "cloudstack_template" "foo" {
+  name = "terraform-test"
+  format = "VHD"
+  hypervisor = "Simulator"
+  os_type = "CentOS 5.6 (64-bit)"
+  url = "%s"
+  zone = "Sandbox-simulator"
+  zones = ["%s"]
+  tags = {
+    terraform-tag = "true"
+  }
+}`, cloudStackTemplateURL, cloudStackSecondZone)
@@ -0,0 +1,364 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceCloudStackUnmanagedInstanceImport() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceCloudStackUnmanagedInstanceImportCreate,
+		Read:   resourceCloudStackUnmanagedInstanceImportRead,
+		Delete: resourceCloudStackUnmanagedInstanceImportDelete,
+
+		Schema: map[string]*schema.Schema{
+			"cluster_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"service_offering": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"display_name": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"host_name": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"template_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"nic_networks": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				ForceNew: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+
+			"nic_ip_addresses": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				ForceNew: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+
+			"disk_offerings": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				ForceNew: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+
+			"details": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				ForceNew: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+
+			"migrate_allowed": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+				ForceNew: true,
+			},
+
+			"forced": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+				ForceNew: true,
+			},
+
+			"import_source": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				RequiredWith: []string{"zone", "hypervisor"},
+			},
+
+			"zone": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"hypervisor": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"host": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"username": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"password": {
+				Type:      schema.TypeString,
+				Optional:  true,
+				Sensitive: true,
+				ForceNew:  true,
+			},
+
+			"disk_path": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"storage_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"project": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"virtual_machine_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceCloudStackUnmanagedInstanceImportCreate(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	name := d.Get("name").(string)
+
+	// Retrieve the service_offering ID
+	serviceofferingid, e := retrieveID(cs, "service_offering", d.Get("service_offering").(string))
+	if e != nil {
+		return e.Error()
+	}
+
+	// Retrieve the IDs of the disk offerings of the data disks
+	diskofferings := make(map[string]string)
+	for disk, offering := range d.Get("disk_offerings").(map[string]interface{}) {
+		diskofferingid, e := retrieveID(cs, "disk_offering", offering.(string))
+		if e != nil {
+			return e.Error()
+		}
+		diskofferings[disk] = diskofferingid
+	}
+
+	var projectid string
+	if project, ok := d.GetOk("project"); ok {
+		id, e := retrieveID(cs, "project", project.(string))
+		if e != nil {
+			return e.Error()
+		}
+		projectid = id
+	}
+
+	var id string
+	if importsource, ok := d.GetOk("import_source"); ok {
+		// Retrieve the zone ID
+		zoneid, e := retrieveID(cs, "zone", d.Get("zone").(string))
+		if e != nil {
+			return e.Error()
+		}
+
+		// Create a new parameter struct
+		p := cs.VirtualMachine.NewImportVmParams(
+			d.Get("cluster_id").(string),
+			d.Get("hypervisor").(string),
+			importsource.(string),
+			name,
+			serviceofferingid,
+			zoneid,
+		)
+
+		for disk, diskofferingid := range diskofferings {
+			p.AddDatadiskofferinglist(map[string]string{
+				"disk":         disk,
+				"diskOffering": diskofferingid,
+			})
+		}
+
+		for nic, ipaddress := range d.Get("nic_ip_addresses").(map[string]interface{}) {
+			p.AddNicipaddresslist(map[string]string{
+				"nic":        nic,
+				"ip4Address": ipaddress.(string),
+			})
+		}
+
+		if nicnetworks, ok := d.GetOk("nic_networks"); ok {
+			p.SetNicnetworklist(stringMapFromSchema(nicnetworks.(map[string]interface{})))
+		}
+
+		if details, ok := d.GetOk("details"); ok {
+			p.SetDetails(stringMapFromSchema(details.(map[string]interface{})))
+		}
+
+		// Set the optional string parameters
+		for key, set := range map[string]func(string){
+			"display_name": p.SetDisplayname,
+			"host_name":    p.SetHostname,
+			"template_id":  p.SetTemplateid,
+			"host":         p.SetHost,
+			"username":     p.SetUsername,
+			"password":     p.SetPassword,
+			"disk_path":    p.SetDiskpath,
+			"storage_id":   p.SetStorageid,
+		} {
+			if v, ok := d.GetOk(key); ok {
+				set(v.(string))
+			}
+		}
+
+		if projectid != "" {
+			p.SetProjectid(projectid)
+		}
+
+		p.SetMigrateallowed(d.Get("migrate_allowed").(bool))
+		p.SetForced(d.Get("forced").(bool))
+
+		log.Printf("[DEBUG] Importing virtual machine %s from %s", name, importsource.(string))
+		r, err := cs.VirtualMachine.ImportVm(p)
+		if err != nil {
+			return fmt.Errorf("Error importing virtual machine %s: %s", name, err)
+		}
+		id = r.Id
+	} else {
+		// Create a new parameter struct
+		p := cs.VirtualMachine.NewImportUnmanagedInstanceParams(
+			d.Get("cluster_id").(string),
+			name,
+			serviceofferingid,
+		)
+
+		if len(diskofferings) > 0 {
+			p.SetDatadiskofferinglist(diskofferings)
+		}
+
+		if nicipaddresses, ok := d.GetOk("nic_ip_addresses"); ok {
+			p.SetNicipaddresslist(stringMapFromSchema(nicipaddresses.(map[string]interface{})))
+		}
+
+		if nicnetworks, ok := d.GetOk("nic_networks"); ok {
+			p.SetNicnetworklist(stringMapFromSchema(nicnetworks.(map[string]interface{})))
+		}
+
+		if details, ok := d.GetOk("details"); ok {
+			p.SetDetails(stringMapFromSchema(details.(map[string]interface{})))
+		}
+
+		// Set the optional string parameters
+		for key, set := range map[string]func(string){
+			"display_name": p.SetDisplayname,
+			"host_name":    p.SetHostname,
+			"template_id":  p.SetTemplateid,
+		} {
+			if v, ok := d.GetOk(key); ok {
+				set(v.(string))
+			}
+		}
+
+		if projectid != "" {
+			p.SetProjectid(projectid)
+		}
+
+		p.SetMigrateallowed(d.Get("migrate_allowed").(bool))
+		p.SetForced(d.Get("forced").(bool))
+
+		log.Printf("[DEBUG] Importing unmanaged instance %s", name)
+		r, err := cs.VirtualMachine.ImportUnmanagedInstance(p)
+		if err != nil {
+			return fmt.Errorf("Error importing unmanaged instance %s: %s", name, err)
+		}
+		id = r.Id
+	}
+
+	d.SetId(id)
+
+	return resourceCloudStackUnmanagedInstanceImportRead(d, meta)
+}
+
+func resourceCloudStackUnmanagedInstanceImportRead(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Get the virtual machine details
+	vm, count, err := cs.VirtualMachine.GetVirtualMachineByID(
+		d.Id(),
+		cloudstack.WithProject(d.Get("project").(string)),
+	)
+	if err != nil {
+		if count == 0 {
+			log.Printf("[DEBUG] Imported instance %s does no longer exist", d.Get("name").(string))
+			d.SetId("")
+			return nil
+		}
+
+		return err
+	}
+
+	d.Set("virtual_machine_id", vm.Id)
+	setValueOrID(d, "project", vm.Project, vm.Projectid)
+
+	return nil
+}
+
+func resourceCloudStackUnmanagedInstanceImportDelete(d *schema.ResourceData, meta interface{}) error {
+	// Once imported, the instance is managed by CloudStack (and usually by a
+	// cloudstack_instance resource), so it is left untouched
+	log.Printf("[INFO] Removing imported instance %s from the state only", d.Id())
+
+	return nil
+}
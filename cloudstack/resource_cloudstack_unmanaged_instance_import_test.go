@@ -0,0 +1,97 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccCloudStackUnmanagedInstanceImport_basic(t *testing.T) {
+	clusterid, name := testAccUnmanagedInstance(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackUnmanagedInstanceImport_basic(clusterid, name),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackUnmanagedInstanceImportExists(
+						"cloudstack_unmanaged_instance_import.foo"),
+					resource.TestCheckResourceAttrPair(
+						"cloudstack_unmanaged_instance_import.foo", "virtual_machine_id",
+						"cloudstack_unmanaged_instance_import.foo", "id"),
+				),
+			},
+		},
+	})
+}
+
+// testAccUnmanagedInstance returns the cluster and name of an unmanaged
+// instance to import, as these cannot be created by the tests
+func testAccUnmanagedInstance(t *testing.T) (string, string) {
+	clusterid := os.Getenv("CLOUDSTACK_UNMANAGED_CLUSTER_ID")
+	name := os.Getenv("CLOUDSTACK_UNMANAGED_INSTANCE")
+	if clusterid == "" || name == "" {
+		t.Skip("This test requires CLOUDSTACK_UNMANAGED_CLUSTER_ID and CLOUDSTACK_UNMANAGED_INSTANCE to be set")
+	}
+
+	return clusterid, name
+}
+
+func testAccCheckCloudStackUnmanagedInstanceImportExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No imported instance ID is set")
+		}
+
+		cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+		vm, _, err := cs.VirtualMachine.GetVirtualMachineByID(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		if vm.Name != rs.Primary.Attributes["name"] {
+			return fmt.Errorf("Bad name: %s", vm.Name)
+		}
+
+		return nil
+	}
+}
+
+func testAccCloudStackUnmanagedInstanceImport_basic(clusterid, name string) string {
+	return fmt.Sprintf(`
+resource "cloudstack_unmanaged_instance_import" "foo" {
+  cluster_id = "%s"
+  name = "%s"
+  service_offering = "Small Instance"
+}`, clusterid, name)
+}
@@ -0,0 +1,185 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/id"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceCloudStackUserData() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceCloudStackUserDataCreate,
+		Read:   resourceCloudStackUserDataRead,
+		Delete: resourceCloudStackUserDataDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: importStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{"name", "name_prefix"},
+			},
+
+			// CloudStack doesn't allow user data to be updated, nor to be deleted
+			// while instances still use it. Generating unique names makes it
+			// possible to replace user data using create_before_destroy.
+			"name_prefix": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"content": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"params": {
+				Type:     schema.TypeList,
+				Optional: true,
+				ForceNew: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+
+			"project": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+		},
+	}
+}
+
+func resourceCloudStackUserDataCreate(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	name := d.Get("name").(string)
+	if prefix, ok := d.GetOk("name_prefix"); ok {
+		name = id.PrefixedUniqueId(prefix.(string))
+	}
+
+	ud, err := getUserData(d.Get("content").(string))
+	if err != nil {
+		return err
+	}
+
+	// Create a new parameter struct
+	p := cs.User.NewRegisterUserDataParams(name, ud)
+
+	if params, ok := d.GetOk("params"); ok {
+		var names []string
+		for _, param := range params.([]interface{}) {
+			names = append(names, param.(string))
+		}
+		p.SetParams(strings.Join(names, ","))
+	}
+
+	// If there is a project supplied, we retrieve and set the project id
+	if err := setProjectid(p, cs, d); err != nil {
+		return err
+	}
+
+	log.Printf("[DEBUG] Registering user data %s", name)
+	r, err := cs.User.RegisterUserData(p)
+	if err != nil {
+		return fmt.Errorf("Error registering user data %s: %s", name, err)
+	}
+
+	d.SetId(r.Id)
+
+	return resourceCloudStackUserDataRead(d, meta)
+}
+
+func resourceCloudStackUserDataRead(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Get the user data details
+	u, count, err := cs.User.GetUserDataByID(
+		d.Id(),
+		cloudstack.WithProject(d.Get("project").(string)),
+	)
+	if err != nil {
+		if count == 0 {
+			log.Printf("[DEBUG] User data %s does no longer exist", d.Get("name").(string))
+			d.SetId("")
+			return nil
+		}
+
+		return err
+	}
+
+	d.Set("name", u.Name)
+
+	// The content is returned base64 encoded, so only update it when it
+	// differs from the (encoded) configured content
+	if ud, err := getUserData(d.Get("content").(string)); err != nil || ud != u.Userdata {
+		d.Set("content", u.Userdata)
+	}
+
+	var params []string
+	if u.Params != "" {
+		for _, param := range strings.Split(u.Params, ",") {
+			params = append(params, strings.TrimSpace(param))
+		}
+	}
+	d.Set("params", params)
+
+	setValueOrID(d, "project", u.Project, u.Projectid)
+
+	return nil
+}
+
+func resourceCloudStackUserDataDelete(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Create a new parameter struct
+	p := cs.User.NewDeleteUserDataParams(d.Id())
+
+	// If there is a project supplied, we retrieve and set the project id
+	if err := setProjectid(p, cs, d); err != nil {
+		return err
+	}
+
+	log.Printf("[INFO] Deleting user data: %s", d.Get("name").(string))
+	if _, err := cs.User.DeleteUserData(p); err != nil {
+		// This is a very poor way to be told the ID does no longer exist :(
+		if strings.Contains(err.Error(), fmt.Sprintf(
+			"Invalid parameter id value=%s due to incorrect long value format, "+
+				"or entity does not exist", d.Id())) {
+			return nil
+		}
+
+		return fmt.Errorf("Error deleting user data %s: %s", d.Get("name").(string), err)
+	}
+
+	return nil
+}
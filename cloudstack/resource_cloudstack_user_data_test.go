@@ -0,0 +1,271 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccCloudStackUserData_basic(t *testing.T) {
+	var userData cloudstack.UserData
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackUserDataDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackUserData_basic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackUserDataExists(
+						"cloudstack_user_data.foo", &userData),
+					testAccCheckCloudStackUserDataAttributes(&userData),
+					resource.TestCheckResourceAttr(
+						"cloudstack_user_data.foo", "params.#", "1"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudStackUserData_instance(t *testing.T) {
+	var instance cloudstack.VirtualMachine
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackUserData_instance,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackInstanceExists(
+						"cloudstack_instance.foobar", &instance),
+					resource.TestCheckResourceAttrPair(
+						"cloudstack_instance.foobar", "user_data_id",
+						"cloudstack_user_data.foo", "id"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudStackUserData_replace(t *testing.T) {
+	var instance cloudstack.VirtualMachine
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackUserData_replace("first"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackInstanceExists(
+						"cloudstack_instance.foobar", &instance),
+					resource.TestMatchResourceAttr(
+						"cloudstack_user_data.foo", "name", regexp.MustCompile("^terraform-user-data-")),
+					resource.TestCheckResourceAttrPair(
+						"cloudstack_instance.foobar", "user_data_id",
+						"cloudstack_user_data.foo", "id"),
+				),
+			},
+
+			{
+				Config: testAccCloudStackUserData_replace("second"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackInstanceExists(
+						"cloudstack_instance.foobar", &instance),
+					resource.TestCheckResourceAttrPair(
+						"cloudstack_instance.foobar", "user_data_id",
+						"cloudstack_user_data.foo", "id"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudStackUserData_import(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackUserDataDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackUserData_basic,
+			},
+
+			{
+				ResourceName:            "cloudstack_user_data.foo",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"content"},
+			},
+		},
+	})
+}
+
+func testAccCheckCloudStackUserDataExists(
+	n string, userData *cloudstack.UserData) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No user data ID is set")
+		}
+
+		cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+		u, _, err := cs.User.GetUserDataByID(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		if u.Id != rs.Primary.ID {
+			return fmt.Errorf("User data not found")
+		}
+
+		*userData = *u
+
+		return nil
+	}
+}
+
+func testAccCheckCloudStackUserDataAttributes(
+	userData *cloudstack.UserData) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+
+		if userData.Name != "terraform-user-data" {
+			return fmt.Errorf("Bad name: %s", userData.Name)
+		}
+
+		if userData.Params != "hostname" {
+			return fmt.Errorf("Bad params: %s", userData.Params)
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckCloudStackUserDataDestroy(s *terraform.State) error {
+	cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "cloudstack_user_data" {
+			continue
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No user data ID is set")
+		}
+
+		_, _, err := cs.User.GetUserDataByID(rs.Primary.ID)
+		if err == nil {
+			return fmt.Errorf("User data %s still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+const testAccCloudStackUserData_basic = `
+resource "cloudstack_user_data" "foo" {
+  name = "terraform-user-data"
+  params = ["hostname"]
+  content = <<-EOF
+    #cloud-config
+    hostname: {{ ds.meta_data.hostname }}
+  EOF
+}`
+
+const testAccCloudStackUserData_instance = `
+resource "cloudstack_network" "foo" {
+  name = "terraform-network"
+  display_text = "terraform-network"
+  cidr = "10.1.1.0/24"
+  network_offering = "DefaultIsolatedNetworkOfferingWithSourceNatService"
+  zone = "Sandbox-simulator"
+}
+
+resource "cloudstack_user_data" "foo" {
+  name = "terraform-user-data"
+  params = ["hostname"]
+  content = <<-EOF
+    #cloud-config
+    hostname: {{ ds.meta_data.hostname }}
+  EOF
+}
+
+resource "cloudstack_instance" "foobar" {
+  name = "terraform-test"
+  display_name = "terraform-test"
+  service_offering= "Small Instance"
+  network_id = cloudstack_network.foo.id
+  template = "CentOS 5.6 (64-bit) no GUI (Simulator)"
+  zone = "Sandbox-simulator"
+  user_data_id = cloudstack_user_data.foo.id
+  user_data_details = {
+    hostname = "terraform-test"
+  }
+  expunge = true
+}`
+
+func testAccCloudStackUserData_replace(role string) string {
+	return fmt.Sprintf(`
+resource "cloudstack_network" "foo" {
+  name = "terraform-network"
+  display_text = "terraform-network"
+  cidr = "10.1.1.0/24"
+  network_offering = "DefaultIsolatedNetworkOfferingWithSourceNatService"
+  zone = "Sandbox-simulator"
+}
+
+resource "cloudstack_user_data" "foo" {
+  name_prefix = "terraform-user-data-"
+  content = <<-EOF
+    #cloud-config
+    runcmd:
+      - echo %s > /etc/role
+  EOF
+
+  lifecycle {
+    create_before_destroy = true
+  }
+}
+
+resource "cloudstack_instance" "foobar" {
+  name = "terraform-test"
+  display_name = "terraform-test"
+  service_offering= "Small Instance"
+  network_id = cloudstack_network.foo.id
+  template = "CentOS 5.6 (64-bit) no GUI (Simulator)"
+  zone = "Sandbox-simulator"
+  user_data_id = cloudstack_user_data.foo.id
+  expunge = true
+}`, role)
+}
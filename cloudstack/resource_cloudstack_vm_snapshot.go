@@ -0,0 +1,265 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceCloudStackVMSnapshot() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceCloudStackVMSnapshotCreate,
+		Read:   resourceCloudStackVMSnapshotRead,
+		Update: resourceCloudStackVMSnapshotUpdate,
+		Delete: resourceCloudStackVMSnapshotDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: importStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"virtual_machine_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"name": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"snapshot_memory": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+				ForceNew: true,
+			},
+
+			"quiescevm": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+				ForceNew: true,
+			},
+
+			"revert_trigger": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			"project": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"type": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"state": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"current": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+
+			"tags": tagsSchema(),
+		},
+	}
+}
+
+func resourceCloudStackVMSnapshotCreate(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	virtualmachineid := d.Get("virtual_machine_id").(string)
+
+	// Create a new parameter struct
+	p := cs.Snapshot.NewCreateVMSnapshotParams(virtualmachineid)
+
+	if name, ok := d.GetOk("name"); ok {
+		p.SetName(name.(string))
+	}
+
+	if description, ok := d.GetOk("description"); ok {
+		p.SetDescription(description.(string))
+	}
+
+	p.SetSnapshotmemory(d.Get("snapshot_memory").(bool))
+	p.SetQuiescevm(d.Get("quiescevm").(bool))
+
+	log.Printf("[DEBUG] Creating VM snapshot of virtual machine %s", virtualmachineid)
+	r, err := cs.Snapshot.CreateVMSnapshot(p)
+	if err != nil {
+		return fmt.Errorf("Error creating VM snapshot of virtual machine %s: %s", virtualmachineid, err)
+	}
+
+	d.SetId(r.Id)
+
+	// Set tags if necessary
+	if err = setTags(cs, d, "VMSnapshot"); err != nil {
+		return fmt.Errorf("Error setting tags on VM snapshot %s: %s", r.Id, err)
+	}
+
+	return resourceCloudStackVMSnapshotRead(d, meta)
+}
+
+func resourceCloudStackVMSnapshotRead(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Create a new parameter struct
+	p := cs.Snapshot.NewListVMSnapshotParams()
+	p.SetVmsnapshotid(d.Id())
+
+	// If there is a project supplied, we retrieve and set the project id
+	if err := setProjectid(p, cs, d); err != nil {
+		return err
+	}
+
+	l, err := cs.Snapshot.ListVMSnapshot(p)
+	if err != nil {
+		return err
+	}
+
+	if l.Count == 0 {
+		log.Printf("[DEBUG] VM snapshot %s does no longer exist", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	s := l.VMSnapshot[0]
+
+	d.Set("virtual_machine_id", s.Virtualmachineid)
+	d.Set("name", s.Name)
+	d.Set("description", s.Description)
+	d.Set("snapshot_memory", s.Type == "DiskAndMemory")
+	d.Set("type", s.Type)
+	d.Set("state", s.State)
+	d.Set("current", s.Current)
+	d.Set("tags", tagsToMap(s.Tags))
+
+	setValueOrID(d, "project", s.Project, s.Projectid)
+
+	return nil
+}
+
+func resourceCloudStackVMSnapshotUpdate(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Check if the revert trigger has changed and if so, revert the virtual
+	// machine to this snapshot
+	if d.HasChange("revert_trigger") {
+		if err := resourceCloudStackVMSnapshotRevert(d, meta); err != nil {
+			return fmt.Errorf("Error reverting to VM snapshot %s: %s", d.Id(), err)
+		}
+	}
+
+	// Check if the tags have changed and if so, update the tags
+	if d.HasChange("tags") {
+		if err := updateTags(cs, d, "VMSnapshot"); err != nil {
+			return fmt.Errorf("Error updating tags on VM snapshot %s: %s", d.Id(), err)
+		}
+	}
+
+	return resourceCloudStackVMSnapshotRead(d, meta)
+}
+
+// resourceCloudStackVMSnapshotRevert reverts the virtual machine to the
+// snapshot. Snapshots without memory can only be reverted while the virtual
+// machine is stopped, so in that case the virtual machine is stopped first and
+// started again afterwards if it was running.
+func resourceCloudStackVMSnapshotRevert(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	virtualmachineid := d.Get("virtual_machine_id").(string)
+
+	vm, _, err := cs.VirtualMachine.GetVirtualMachineByID(
+		virtualmachineid,
+		cloudstack.WithProject(d.Get("project").(string)),
+	)
+	if err != nil {
+		return err
+	}
+
+	restart := vm.State == "Running" && !d.Get("snapshot_memory").(bool)
+	if restart {
+		_, err := cs.VirtualMachine.StopVirtualMachine(
+			cs.VirtualMachine.NewStopVirtualMachineParams(virtualmachineid))
+		if err != nil {
+			return fmt.Errorf("Error stopping virtual machine %s: %s", virtualmachineid, err)
+		}
+	}
+
+	log.Printf("[DEBUG] Reverting virtual machine %s to VM snapshot %s", virtualmachineid, d.Id())
+	_, err = cs.Snapshot.RevertToVMSnapshot(cs.Snapshot.NewRevertToVMSnapshotParams(d.Id()))
+	if err != nil {
+		return err
+	}
+
+	if restart {
+		_, err := cs.VirtualMachine.StartVirtualMachine(
+			cs.VirtualMachine.NewStartVirtualMachineParams(virtualmachineid))
+		if err != nil {
+			return fmt.Errorf("Error starting virtual machine %s: %s", virtualmachineid, err)
+		}
+	}
+
+	return nil
+}
+
+func resourceCloudStackVMSnapshotDelete(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Create a new parameter struct
+	p := cs.Snapshot.NewDeleteVMSnapshotParams(d.Id())
+
+	log.Printf("[INFO] Deleting VM snapshot: %s", d.Id())
+	if _, err := cs.Snapshot.DeleteVMSnapshot(p); err != nil {
+		// This is a very poor way to be told the ID does no longer exist :(
+		if strings.Contains(err.Error(), fmt.Sprintf(
+			"Invalid parameter id value=%s due to incorrect long value format, "+
+				"or entity does not exist", d.Id())) {
+			return nil
+		}
+
+		return fmt.Errorf("Error deleting VM snapshot %s: %s", d.Id(), err)
+	}
+
+	return nil
+}
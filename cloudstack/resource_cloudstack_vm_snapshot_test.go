@@ -0,0 +1,222 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccCloudStackVMSnapshot_basic(t *testing.T) {
+	var snapshot cloudstack.VMSnapshot
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackVMSnapshotDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackVMSnapshot_basic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackVMSnapshotExists(
+						"cloudstack_vm_snapshot.foo", &snapshot),
+					testAccCheckCloudStackVMSnapshotAttributes(&snapshot),
+					resource.TestCheckResourceAttr(
+						"cloudstack_vm_snapshot.foo", "type", "Disk"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudStackVMSnapshot_revert(t *testing.T) {
+	var snapshot cloudstack.VMSnapshot
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackVMSnapshotDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackVMSnapshot_basic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackVMSnapshotExists(
+						"cloudstack_vm_snapshot.foo", &snapshot),
+				),
+			},
+
+			{
+				Config: testAccCloudStackVMSnapshot_revert,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackVMSnapshotExists(
+						"cloudstack_vm_snapshot.foo", &snapshot),
+					resource.TestCheckResourceAttr(
+						"cloudstack_vm_snapshot.foo", "current", "true"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudStackVMSnapshot_import(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackVMSnapshotDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackVMSnapshot_basic,
+			},
+
+			{
+				ResourceName:            "cloudstack_vm_snapshot.foo",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"quiescevm"},
+			},
+		},
+	})
+}
+
+func testAccCheckCloudStackVMSnapshotExists(
+	n string, snapshot *cloudstack.VMSnapshot) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No VM snapshot ID is set")
+		}
+
+		cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+		p := cs.Snapshot.NewListVMSnapshotParams()
+		p.SetVmsnapshotid(rs.Primary.ID)
+
+		l, err := cs.Snapshot.ListVMSnapshot(p)
+		if err != nil {
+			return err
+		}
+
+		if l.Count != 1 || l.VMSnapshot[0].Id != rs.Primary.ID {
+			return fmt.Errorf("VM snapshot not found")
+		}
+
+		*snapshot = *l.VMSnapshot[0]
+
+		return nil
+	}
+}
+
+func testAccCheckCloudStackVMSnapshotAttributes(
+	snapshot *cloudstack.VMSnapshot) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+
+		if snapshot.Name != "terraform-snapshot" {
+			return fmt.Errorf("Bad name: %s", snapshot.Name)
+		}
+
+		if snapshot.Description != "terraform test snapshot" {
+			return fmt.Errorf("Bad description: %s", snapshot.Description)
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckCloudStackVMSnapshotDestroy(s *terraform.State) error {
+	cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "cloudstack_vm_snapshot" {
+			continue
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No VM snapshot ID is set")
+		}
+
+		p := cs.Snapshot.NewListVMSnapshotParams()
+		p.SetVmsnapshotid(rs.Primary.ID)
+
+		l, err := cs.Snapshot.ListVMSnapshot(p)
+		if err == nil && l.Count > 0 {
+			return fmt.Errorf("VM snapshot %s still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+const testAccCloudStackVMSnapshot_basic = `
+resource "cloudstack_network" "foo" {
+  name = "terraform-network"
+  display_text = "terraform-network"
+  cidr = "10.1.1.0/24"
+  network_offering = "DefaultIsolatedNetworkOfferingWithSourceNatService"
+  zone = "Sandbox-simulator"
+}
+
+resource "cloudstack_instance" "foobar" {
+  name = "terraform-test"
+  display_name = "terraform-test"
+  service_offering= "Small Instance"
+  network_id = cloudstack_network.foo.id
+  template = "CentOS 5.6 (64-bit) no GUI (Simulator)"
+  zone = "Sandbox-simulator"
+  expunge = true
+}
+
+resource "cloudstack_vm_snapshot" "foo" {
+  virtual_machine_id = cloudstack_instance.foobar.id
+  name = "terraform-snapshot"
+  description = "terraform test snapshot"
+}`
+
+const testAccCloudStackVMSnapshot_revert = `
+resource "cloudstack_network" "foo" {
+  name = "terraform-network"
+  display_text = "terraform-network"
+  cidr = "10.1.1.0/24"
+  network_offering = "DefaultIsolatedNetworkOfferingWithSourceNatService"
+  zone = "Sandbox-simulator"
+}
+
+resource "cloudstack_instance" "foobar" {
+  name = "terraform-test"
+  display_name = "terraform-test"
+  service_offering= "Small Instance"
+  network_id = cloudstack_network.foo.id
+  template = "CentOS 5.6 (64-bit) no GUI (Simulator)"
+  zone = "Sandbox-simulator"
+  expunge = true
+}
+
+resource "cloudstack_vm_snapshot" "foo" {
+  virtual_machine_id = cloudstack_instance.foobar.id
+  name = "terraform-snapshot"
+  description = "terraform test snapshot"
+  revert_trigger = "1"
+}`
@@ -20,96 +20,440 @@
 package cloudstack
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"log"
+	"strings"
 
 	"github.com/apache/cloudstack-go/v2/cloudstack"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
-func resourceCloudStackVolume() *schema.Resource {
-	return &schema.Resource{
-		Create: resourceCloudStackVolumeCreate,
-		Read:   resourceCloudStackVolumeRead,
-		Delete: resourceCloudStackVolumeDelete,
-		Schema: map[string]*schema.Schema{
-			"name": {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
+var (
+	_ resource.ResourceWithConfigure   = &volumeResource{}
+	_ resource.ResourceWithImportState = &volumeResource{}
+	_ resource.ResourceWithMoveState   = &volumeResource{}
+)
+
+func newVolumeResource(p *CloudstackProvider) func() resource.Resource {
+	return func() resource.Resource {
+		return &volumeResource{provider: p}
+	}
+}
+
+type volumeResource struct {
+	ResourceWithConfigure
+
+	provider *CloudstackProvider
+}
+
+type volumeResourceModel struct {
+	Id             types.String `tfsdk:"id"`
+	Name           types.String `tfsdk:"name"`
+	DiskOfferingId types.String `tfsdk:"disk_offering_id"`
+	ZoneId         types.String `tfsdk:"zone_id"`
+	Size           types.Int64  `tfsdk:"size"`
+	ShrinkOk       types.Bool   `tfsdk:"shrink_ok"`
+	Project        types.String `tfsdk:"project"`
+	Tags           types.Map    `tfsdk:"tags"`
+}
+
+func (r *volumeResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_volume"
+}
+
+func (r *volumeResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Description: "The name of the volume",
+				Required:    true,
+			},
+			"disk_offering_id": schema.StringAttribute{
+				Description: "The ID of the disk offering for the volume",
+				Required:    true,
 			},
-			"disk_offering_id": {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
+			"zone_id": schema.StringAttribute{
+				Description: "The ID of the zone where the volume will be created",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
-			"zone_id": {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
+			"size": schema.Int64Attribute{
+				Description: "The size of the volume in GB",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"shrink_ok": schema.BoolAttribute{
+				Description: "Whether the volume is allowed to shrink when resizing",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+			},
+			"project": schema.StringAttribute{
+				Description: "The name or ID of the project to create the volume in",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplaceIfConfigured(),
+				},
+			},
+			"tags": schema.MapAttribute{
+				Description: "A mapping of tags to assign to the volume",
+				ElementType: types.StringType,
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.UseStateForUnknown(),
+				},
 			},
 		},
 	}
 }
 
-func resourceCloudStackVolumeCreate(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
-	name := d.Get("name").(string)
-	disk_offering_id := d.Get("disk_offering_id").(string)
-	zone_id := d.Get("zone_id").(string)
+func (r *volumeResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan volumeResourceModel
 
-	//Create a new parameter struct
-	p := cs.Volume.NewCreateVolumeParams()
-	p.SetDiskofferingid(disk_offering_id)
-	p.SetZoneid(zone_id)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	name := plan.Name.ValueString()
+
+	// Create a new parameter struct
+	p := r.client.Volume.NewCreateVolumeParams()
 	p.SetName(name)
+	p.SetDiskofferingid(plan.DiskOfferingId.ValueString())
+	p.SetZoneid(plan.ZoneId.ValueString())
 
-	log.Printf("[DEBUG] Creating Volume %s", name)
-	v, err := cs.Volume.CreateVolume(p)
+	if !plan.Size.IsUnknown() && !plan.Size.IsNull() {
+		p.SetSize(plan.Size.ValueInt64())
+	}
+
+	// If there is a project supplied, we retrieve and set the project id
+	if project := plan.Project.ValueString(); project != "" {
+		projectid, e := retrieveID(r.client, "project", project)
+		if e != nil {
+			resp.Diagnostics.AddError("Error creating volume", e.Error().Error())
+			return
+		}
+		p.SetProjectid(projectid)
+	}
 
+	log.Printf("[DEBUG] Creating Volume %s", name)
+	v, err := r.client.Volume.CreateVolume(p)
 	if err != nil {
-		return err
+		resp.Diagnostics.AddError("Error creating volume", fmt.Sprintf("Error creating volume %s: %s", name, err))
+		return
 	}
 
 	log.Printf("[DEBUG] Volume %s successfully created", name)
-	d.SetId(v.Id)
+	plan.Id = types.StringValue(v.Id)
 
-	return resourceCloudStackVolumeRead(d, meta)
+	// Set tags if necessary
+	if !plan.Tags.IsUnknown() && !plan.Tags.IsNull() {
+		resp.Diagnostics.Append(r.updateTags(ctx, v.Id, types.MapNull(types.StringType), plan.Tags)...)
+	}
+
+	if _, err := r.read(ctx, &plan); err != nil {
+		resp.Diagnostics.AddError("Error reading volume", err.Error())
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
-func resourceCloudStackVolumeRead(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
-	log.Printf("[DEBUG] Retrieving Volume %s", d.Get("name").(string))
 
-	// Get the Volume details
-	v, count, err := cs.Volume.GetVolumeByName(d.Get("name").(string))
+func (r *volumeResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state volumeResourceModel
 
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	found, err := r.read(ctx, &state)
 	if err != nil {
-		if count == 0 {
-			log.Printf("[DEBUG] Volume %s does no longer exist", d.Get("name").(string))
-			d.SetId("")
-			return nil
+		resp.Diagnostics.AddError("Error reading volume", err.Error())
+		return
+	}
+
+	if !found {
+		log.Printf("[DEBUG] Volume %s does no longer exist", state.Id.ValueString())
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *volumeResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state volumeResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	id := state.Id.ValueString()
+	name := plan.Name.ValueString()
+
+	// Check if the name has changed and if so, rename the volume
+	if !plan.Name.Equal(state.Name) {
+		p := r.client.Volume.NewUpdateVolumeParams()
+		p.SetId(id)
+		p.SetName(name)
+
+		if _, err := r.client.Volume.UpdateVolume(p); err != nil {
+			resp.Diagnostics.AddError("Error updating volume", fmt.Sprintf("Error renaming volume %s: %s", id, err))
+			return
 		}
-		return err
 	}
 
-	d.SetId(v.Id)
-	d.Set("name", v.Name)
-	d.Set("disk_offering_id", v.Diskofferingid)
-	d.Set("zone_id", v.Zoneid)
+	var size int64
+	if !plan.Size.IsUnknown() && !plan.Size.IsNull() && !plan.Size.Equal(state.Size) {
+		size = plan.Size.ValueInt64()
+	}
 
-	return nil
+	if !plan.DiskOfferingId.Equal(state.DiskOfferingId) {
+		// Change the disk offering (and size) of the existing volume
+		p := r.client.Volume.NewChangeOfferingForVolumeParams(plan.DiskOfferingId.ValueString(), id)
+		if size != 0 {
+			p.SetSize(size)
+		}
+		p.SetShrinkok(plan.ShrinkOk.ValueBool())
+		p.SetAutomigrate(true)
+
+		if _, err := r.client.Volume.ChangeOfferingForVolume(p); err != nil {
+			resp.Diagnostics.AddError("Error updating volume", fmt.Sprintf("Error changing disk offering for volume %s: %s", name, err))
+			return
+		}
+	} else if size != 0 {
+		// Resize the existing volume
+		if _, err := resizeVolume(r.client, id, "", size, plan.ShrinkOk.ValueBool()); err != nil {
+			resp.Diagnostics.AddError("Error updating volume", fmt.Sprintf("Error resizing volume %s: %s", name, err))
+			return
+		}
+	}
+
+	// Check if the tags have changed and if so, update the tags
+	if !plan.Tags.IsUnknown() && !plan.Tags.Equal(state.Tags) {
+		resp.Diagnostics.Append(r.updateTags(ctx, id, state.Tags, plan.Tags)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	plan.Id = state.Id
+
+	if _, err := r.read(ctx, &plan); err != nil {
+		resp.Diagnostics.AddError("Error reading volume", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
-func resourceCloudStackVolumeDelete(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+func (r *volumeResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state volumeResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	id := state.Id.ValueString()
 
 	// Create a new parameter struct
-	p := cs.Volume.NewDeleteVolumeParams(d.Id())
-	_, err := cs.Volume.DeleteVolume(p)
+	p := r.client.Volume.NewDeleteVolumeParams(id)
+
+	log.Printf("[INFO] Deleting Volume: %s", id)
+	if _, err := r.client.Volume.DeleteVolume(p); err != nil {
+		// This is a very poor way to be told the ID does no longer exist :(
+		if strings.Contains(err.Error(), fmt.Sprintf(
+			"Invalid parameter id value=%s due to incorrect long value format, "+
+				"or entity does not exist", id)) {
+			return
+		}
+
+		resp.Diagnostics.AddError("Error deleting volume", fmt.Sprintf("Error deleting volume %s: %s", id, err))
+	}
+}
+
+func (r *volumeResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Try to split the ID to extract the optional project name.
+	s := strings.SplitN(req.ID, "/", 2)
+	if len(s) == 2 {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("project"), s[0])...)
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), s[len(s)-1])...)
+}
+
+// MoveState moves the state of a cloudstack_disk to a cloudstack_volume. The
+// names of the disk offering and zone are resolved to the IDs the volume uses.
+func (r *volumeResource) MoveState(ctx context.Context) []resource.StateMover {
+	return []resource.StateMover{
+		{
+			StateMover: func(ctx context.Context, req resource.MoveStateRequest, resp *resource.MoveStateResponse) {
+				if req.SourceTypeName != "cloudstack_disk" || !strings.HasSuffix(req.SourceProviderAddress, "/cloudstack") {
+					return
+				}
+
+				var disk struct {
+					Id           string            `json:"id"`
+					Name         string            `json:"name"`
+					DiskOffering string            `json:"disk_offering"`
+					Zone         string            `json:"zone"`
+					Size         int64             `json:"size"`
+					ShrinkOk     bool              `json:"shrink_ok"`
+					Project      string            `json:"project"`
+					Tags         map[string]string `json:"tags"`
+				}
+
+				if err := json.Unmarshal(req.SourceRawState.JSON, &disk); err != nil {
+					resp.Diagnostics.AddError(
+						"Unable to Move Resource State",
+						fmt.Sprintf("Error decoding the cloudstack_disk state: %s", err),
+					)
+					return
+				}
+
+				// The framework does not configure resources before moving state,
+				// so use the client of the configured provider
+				if r.provider == nil || r.provider.client == nil {
+					resp.Diagnostics.AddError(
+						"Unable to Move Resource State",
+						"The provider must be configured to resolve the disk offering and zone of the cloudstack_disk",
+					)
+					return
+				}
+				cs := r.provider.client
+
+				diskofferingid, e := retrieveID(cs, "disk_offering", disk.DiskOffering)
+				if e != nil {
+					resp.Diagnostics.AddError("Unable to Move Resource State", e.Error().Error())
+					return
+				}
+
+				zoneid, e := retrieveID(cs, "zone", disk.Zone)
+				if e != nil {
+					resp.Diagnostics.AddError("Unable to Move Resource State", e.Error().Error())
+					return
+				}
+
+				project := types.StringNull()
+				if disk.Project != "" {
+					project = types.StringValue(disk.Project)
+				}
+
+				if disk.Tags == nil {
+					disk.Tags = make(map[string]string)
+				}
+
+				tags, diags := types.MapValueFrom(ctx, types.StringType, disk.Tags)
+				resp.Diagnostics.Append(diags...)
+				if resp.Diagnostics.HasError() {
+					return
+				}
 
+				resp.Diagnostics.Append(resp.TargetState.Set(ctx, volumeResourceModel{
+					Id:             types.StringValue(disk.Id),
+					Name:           types.StringValue(disk.Name),
+					DiskOfferingId: types.StringValue(diskofferingid),
+					ZoneId:         types.StringValue(zoneid),
+					Size:           types.Int64Value(disk.Size),
+					ShrinkOk:       types.BoolValue(disk.ShrinkOk),
+					Project:        project,
+					Tags:           tags,
+				})...)
+			},
+		},
+	}
+}
+
+// read refreshes the model with the current volume details and reports if
+// the volume still exists
+func (r *volumeResource) read(ctx context.Context, model *volumeResourceModel) (bool, error) {
+	// Get the volume details
+	v, count, err := r.client.Volume.GetVolumeByID(
+		model.Id.ValueString(),
+		cloudstack.WithProject(model.Project.ValueString()),
+	)
 	if err != nil {
-		return fmt.Errorf("Error deleting Volume: %s", err)
+		if count == 0 {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	tags, diags := types.MapValueFrom(ctx, types.StringType, tagsToMap(v.Tags))
+	if diags.HasError() {
+		return false, fmt.Errorf("Error reading the tags of volume %s", v.Id)
+	}
+
+	model.Name = types.StringValue(v.Name)
+	model.DiskOfferingId = types.StringValue(v.Diskofferingid)
+	model.ZoneId = types.StringValue(v.Zoneid)
+	model.Size = types.Int64Value(v.Size / (1024 * 1024 * 1024)) // Needed to get GB's again
+	model.Tags = tags
+
+	if model.ShrinkOk.IsUnknown() || model.ShrinkOk.IsNull() {
+		model.ShrinkOk = types.BoolValue(false)
+	}
+
+	if cloudstack.IsID(model.Project.ValueString()) {
+		model.Project = types.StringValue(v.Projectid)
+	} else {
+		model.Project = types.StringValue(v.Project)
+	}
+
+	return true, nil
+}
+
+// updateTags updates the tags of the volume from the old to the new tags
+func (r *volumeResource) updateTags(ctx context.Context, id string, o, n types.Map) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	oldTags := make(map[string]string)
+	if !o.IsNull() && !o.IsUnknown() {
+		diags.Append(o.ElementsAs(ctx, &oldTags, false)...)
+	}
+
+	newTags := make(map[string]string)
+	if !n.IsNull() && !n.IsUnknown() {
+		diags.Append(n.ElementsAs(ctx, &newTags, false)...)
+	}
+
+	if diags.HasError() {
+		return diags
+	}
+
+	if err := updateResourceTags(r.client, id, "Volume", oldTags, newTags); err != nil {
+		diags.AddError("Error updating tags", fmt.Sprintf("Error updating tags on volume %s: %s", id, err))
 	}
 
-	return nil
+	return diags
 }
@@ -0,0 +1,269 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccCloudStackVolume_basic(t *testing.T) {
+	var volume cloudstack.Volume
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccMuxProvider,
+		CheckDestroy:             testAccCheckCloudStackVolumeDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackVolume_basic(10),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackVolumeExists(
+						"cloudstack_volume.foo", &volume),
+					resource.TestCheckResourceAttr(
+						"cloudstack_volume.foo", "name", "terraform-volume"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_volume.foo", "size", "10"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_volume.foo", "tags.terraform-tag", "true"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudStackVolume_resize(t *testing.T) {
+	var before, after cloudstack.Volume
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccMuxProvider,
+		CheckDestroy:             testAccCheckCloudStackVolumeDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackVolume_basic(10),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackVolumeExists(
+						"cloudstack_volume.foo", &before),
+				),
+			},
+
+			{
+				Config: testAccCloudStackVolume_basic(20),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackVolumeExists(
+						"cloudstack_volume.foo", &after),
+					testAccCheckCloudStackVolumeNotRecreated(&before, &after),
+					resource.TestCheckResourceAttr(
+						"cloudstack_volume.foo", "size", "20"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudStackVolume_import(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccMuxProvider,
+		CheckDestroy:             testAccCheckCloudStackVolumeDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackVolume_basic(10),
+			},
+
+			{
+				ResourceName:      "cloudstack_volume.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccCloudStackVolume_moveFromDisk(t *testing.T) {
+	var before, after cloudstack.Volume
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccMuxProvider,
+		CheckDestroy:             testAccCheckCloudStackVolumeDestroy,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackVolume_disk,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackVolumeExists(
+						"cloudstack_disk.foo", &before),
+				),
+			},
+
+			{
+				Config: testAccCloudStackVolume_moved,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackVolumeExists(
+						"cloudstack_volume.foo", &after),
+					testAccCheckCloudStackVolumeNotRecreated(&before, &after),
+					resource.TestCheckResourceAttrPair(
+						"cloudstack_volume.foo", "disk_offering_id", "cloudstack_disk_offering.foo", "id"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckCloudStackVolumeExists(
+	n string, volume *cloudstack.Volume) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No volume ID is set")
+		}
+
+		cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+		v, _, err := cs.Volume.GetVolumeByID(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		if v.Id != rs.Primary.ID {
+			return fmt.Errorf("Volume not found")
+		}
+
+		*volume = *v
+
+		return nil
+	}
+}
+
+func testAccCheckCloudStackVolumeNotRecreated(
+	before, after *cloudstack.Volume) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if before.Id != after.Id {
+			return fmt.Errorf("Volume was recreated: %s != %s", before.Id, after.Id)
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckCloudStackVolumeDestroy(s *terraform.State) error {
+	cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "cloudstack_volume" {
+			continue
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No volume ID is set")
+		}
+
+		_, _, err := cs.Volume.GetVolumeByID(rs.Primary.ID)
+		if err == nil {
+			return fmt.Errorf("Volume %s still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+func testAccCloudStackVolume_basic(size int) string {
+	return fmt.Sprintf(`
+resource "cloudstack_disk_offering" "foo" {
+  name = "terraform-disk-offering"
+  display_text = "terraform-disk-offering"
+  customized = true
+}
+
+data "cloudstack_zone" "foo" {
+  filter {
+    name = "name"
+    value = "Sandbox-simulator"
+  }
+}
+
+resource "cloudstack_volume" "foo" {
+  name = "terraform-volume"
+  disk_offering_id = cloudstack_disk_offering.foo.id
+  zone_id = data.cloudstack_zone.foo.id
+  size = %d
+  tags = {
+    terraform-tag = "true"
+  }
+}`, size)
+}
+
+const testAccCloudStackVolume_disk = `
+resource "cloudstack_disk_offering" "foo" {
+  name = "terraform-disk-offering"
+  display_text = "terraform-disk-offering"
+  disk_size = 1
+}
+
+data "cloudstack_zone" "foo" {
+  filter {
+    name = "name"
+    value = "Sandbox-simulator"
+  }
+}
+
+resource "cloudstack_disk" "foo" {
+  name = "terraform-volume"
+  attach = false
+  disk_offering = cloudstack_disk_offering.foo.id
+  zone = data.cloudstack_zone.foo.id
+}`
+
+const testAccCloudStackVolume_moved = `
+resource "cloudstack_disk_offering" "foo" {
+  name = "terraform-disk-offering"
+  display_text = "terraform-disk-offering"
+  disk_size = 1
+}
+
+data "cloudstack_zone" "foo" {
+  filter {
+    name = "name"
+    value = "Sandbox-simulator"
+  }
+}
+
+moved {
+  from = cloudstack_disk.foo
+  to   = cloudstack_volume.foo
+}
+
+resource "cloudstack_volume" "foo" {
+  name = "terraform-volume"
+  disk_offering_id = cloudstack_disk_offering.foo.id
+  zone_id = data.cloudstack_zone.foo.id
+}`
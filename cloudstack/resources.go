@@ -21,6 +21,7 @@ package cloudstack
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"regexp"
@@ -126,6 +127,78 @@ func retrieveTemplateID(cs *cloudstack.CloudStackClient, zoneid, value string) (
 	return id, nil
 }
 
+func retrieveIsoID(cs *cloudstack.CloudStackClient, zoneid, value string) (id string, e *retrieveError) {
+	// If the supplied value isn't a ID, try to retrieve the ID ourselves
+	if cloudstack.IsID(value) {
+		return value, nil
+	}
+
+	log.Printf("[DEBUG] Retrieving ID of ISO: %s", value)
+
+	// Ignore count, since an error is returned if there is no exact match
+	id, _, err := cs.ISO.GetIsoID(value, "executable", zoneid)
+	if err != nil {
+		return id, &retrieveError{name: "iso", value: value, err: err}
+	}
+
+	return id, nil
+}
+
+// suppressCaseDiff suppresses diffs that only differ in case, for values
+// that CloudStack accepts case insensitively but returns in its own casing
+func suppressCaseDiff(k, old, new string, d *schema.ResourceData) bool {
+	return strings.EqualFold(old, new)
+}
+
+// stringMapFromSchema converts a map from the schema to a map of strings
+func stringMapFromSchema(m map[string]interface{}) map[string]string {
+	result := make(map[string]string, len(m))
+	for k, v := range m {
+		result[k] = v.(string)
+	}
+
+	return result
+}
+
+// customRequest executes an API request that is not supported by the client
+// and unmarshals the response into result
+func customRequest(cs *cloudstack.CloudStackClient, api string, p *cloudstack.CustomServiceParams, result interface{}) error {
+	custom, ok := cs.Custom.(*cloudstack.CustomService)
+	if !ok {
+		return fmt.Errorf("Unexpected custom service type %T", cs.Custom)
+	}
+
+	return custom.CustomPostRequest(api, p, result)
+}
+
+// customAsyncRequest executes an asynchronous API request that is not
+// supported by the client, waits for the job to finish and unmarshals the
+// job result into result (if not nil)
+func customAsyncRequest(
+	cs *cloudstack.CloudStackClient,
+	api string,
+	p *cloudstack.CustomServiceParams,
+	result interface{},
+	timeout time.Duration) error {
+	var job struct {
+		JobID string `json:"jobid"`
+	}
+	if err := customRequest(cs, api, p, &job); err != nil {
+		return err
+	}
+
+	b, err := cs.GetAsyncJobResult(job.JobID, int64(timeout.Seconds()))
+	if err != nil {
+		return err
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	return json.Unmarshal(b, result)
+}
+
 // RetryFunc is the function retried n times
 type RetryFunc func() (interface{}, error)
 
@@ -147,6 +220,30 @@ func Retry(n int, f RetryFunc) (interface{}, error) {
 	return nil, lastErr
 }
 
+// waitForReady calls ready every 10 seconds until it reports the named
+// resource is ready, or returns an error once the timeout (in seconds) has passed
+func waitForReady(timeout int64, name string, ready func() (bool, error)) error {
+	currentTime := time.Now().Unix()
+	for {
+		// Start with the sleep so the action has a few seconds to process
+		// the request correctly
+		time.Sleep(10 * time.Second)
+
+		ok, err := ready()
+		if err != nil {
+			return err
+		}
+
+		if ok {
+			return nil
+		}
+
+		if time.Now().Unix()-currentTime > timeout {
+			return fmt.Errorf("Timeout while waiting for %s to become ready", name)
+		}
+	}
+}
+
 // If there is a project supplied, we retrieve and set the project id
 func setProjectid(p cloudstack.ProjectIDSetter, cs *cloudstack.CloudStackClient, d *schema.ResourceData) error {
 	if project, ok := d.GetOk("project"); ok {
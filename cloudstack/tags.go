@@ -59,14 +59,20 @@ func updateTags(cs *cloudstack.CloudStackClient, d *schema.ResourceData, resourc
 	o := oraw.(map[string]interface{})
 	n := nraw.(map[string]interface{})
 
-	remove, create := diffTags(tagsFromSchema(o), tagsFromSchema(n))
+	return updateResourceTags(cs, d.Id(), resourcetype, tagsFromSchema(o), tagsFromSchema(n))
+}
+
+// updateResourceTags removes the obsolete and creates the new tags of the
+// resource with the given ID
+func updateResourceTags(cs *cloudstack.CloudStackClient, id string, resourcetype string, o, n map[string]string) error {
+	remove, create := diffTags(o, n)
 	log.Printf("[DEBUG] tags to remove: %v", remove)
 	log.Printf("[DEBUG] tags to create: %v", create)
 
 	// First remove any obsolete tags
 	if len(remove) > 0 {
-		log.Printf("[DEBUG] Removing tags: %v from %s", remove, d.Id())
-		p := cs.Resourcetags.NewDeleteTagsParams([]string{d.Id()}, resourcetype)
+		log.Printf("[DEBUG] Removing tags: %v from %s", remove, id)
+		p := cs.Resourcetags.NewDeleteTagsParams([]string{id}, resourcetype)
 		p.SetTags(remove)
 		_, err := cs.Resourcetags.DeleteTags(p)
 		if err != nil {
@@ -76,8 +82,8 @@ func updateTags(cs *cloudstack.CloudStackClient, d *schema.ResourceData, resourc
 
 	// Then add any new tags
 	if len(create) > 0 {
-		log.Printf("[DEBUG] Creating tags: %v for %s", create, d.Id())
-		p := cs.Resourcetags.NewCreateTagsParams([]string{d.Id()}, resourcetype, create)
+		log.Printf("[DEBUG] Creating tags: %v for %s", create, id)
+		p := cs.Resourcetags.NewCreateTagsParams([]string{id}, resourcetype, create)
 		_, err := cs.Resourcetags.CreateTags(p)
 		if err != nil {
 			return err
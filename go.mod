@@ -1,6 +1,7 @@
 module github.com/terraform-providers/terraform-provider-cloudstack
 
 require (
+	github.com/ProtonMail/go-crypto v1.1.3
 	github.com/apache/cloudstack-go/v2 v2.17.0
 	github.com/go-ini/ini v1.67.0
 	github.com/hashicorp/go-multierror v1.1.1
@@ -13,7 +14,6 @@ require (
 )
 
 require (
-	github.com/ProtonMail/go-crypto v1.1.3 // indirect
 	github.com/agext/levenshtein v1.2.2 // indirect
 	github.com/apparentlymart/go-textseg/v15 v15.0.0 // indirect
 	github.com/cloudflare/circl v1.3.7 // indirect